@@ -28,6 +28,18 @@ func (p *Program) TokenLiteral() string {
 	return p.Statements[0].TokenLiteral()
 }
 
+// Documented returns the top-level let statements that have a doc comment,
+// in source order.
+func (p *Program) Documented() []*LetStatement {
+	var lets []*LetStatement
+	for _, s := range p.Statements {
+		if let, ok := s.(*LetStatement); ok && let != nil && let.Doc != "" {
+			lets = append(lets, let)
+		}
+	}
+	return lets
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 
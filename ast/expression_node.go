@@ -2,6 +2,9 @@ package ast
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/antklim/go-inter/token"
 )
@@ -36,6 +39,125 @@ func (i *IntegerLiteral) String() string {
 	return i.Token.Literal
 }
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (f *FloatLiteral) expressionNode() {}
+
+func (f *FloatLiteral) TokenLiteral() string {
+	return f.Token.Literal
+}
+
+func (f *FloatLiteral) String() string {
+	return f.Token.Literal
+}
+
+type StringLiteral struct {
+	Token token.Token // the token.STRING token, Literal holds the quoted source text
+	Value string
+}
+
+func (s *StringLiteral) expressionNode() {}
+
+func (s *StringLiteral) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s *StringLiteral) String() string {
+	return s.Token.Literal
+}
+
+// InterpolatedString is a string literal with interpolations, such as
+// "Hello ${name}!". Its Parts are the text between the interpolations, empty
+// text is left out, and the interpolated expressions. A text part is a
+// *StringLiteral that holds the token.STRING_START, token.STRING_MID or
+// token.STRING_END token it comes from.
+type InterpolatedString struct {
+	Token token.Token // the token.STRING_START token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode() {}
+
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// String returns the literal in canonical form: the text is escaped the
+// same way whatever its spelling in the source.
+func (is *InterpolatedString) String() string {
+	var out strings.Builder
+
+	out.WriteByte('"')
+	for _, part := range is.Parts {
+		if s, ok := part.(*StringLiteral); ok && s.Token.Type != token.STRING {
+			writeEscaped(&out, s.Value)
+			continue
+		}
+		out.WriteString("${")
+		out.WriteString(part.String())
+		out.WriteByte('}')
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
+
+// writeEscaped writes s to out as the text of a double-quoted string literal.
+func writeEscaped(out *strings.Builder, s string) {
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '$' && strings.HasPrefix(s[i+1:], "{"):
+			out.WriteString(`\$`)
+		case r == '\n':
+			out.WriteString(`\n`)
+		case r == '\t':
+			out.WriteString(`\t`)
+		case r == '\r':
+			out.WriteString(`\r`)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(out, `\u{%X}`, r)
+		default:
+			out.WriteRune(r)
+		}
+	}
+}
+
+type CharLiteral struct {
+	Token token.Token // the token.CHAR token, Literal holds the quoted source text
+	Value rune
+}
+
+func (c *CharLiteral) expressionNode() {}
+
+func (c *CharLiteral) TokenLiteral() string {
+	return c.Token.Literal
+}
+
+func (c *CharLiteral) String() string {
+	return c.Token.Literal
+}
+
+type Boolean struct {
+	Token token.Token // the token.TRUE or token.FALSE token
+	Value bool
+}
+
+func (b *Boolean) expressionNode() {}
+
+func (b *Boolean) TokenLiteral() string {
+	return b.Token.Literal
+}
+
+func (b *Boolean) String() string {
+	return b.Token.Literal
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -83,3 +205,145 @@ func (ie *InfixExpression) String() string {
 
 	return out.String()
 }
+
+type CallExpression struct {
+	Token     token.Token // the token.LPAREN token
+	Function  Expression  // identifier or expression that evaluates to a function
+	Arguments []Expression
+}
+
+func (ce *CallExpression) expressionNode() {}
+
+func (ce *CallExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+func (ce *CallExpression) String() string {
+	var out bytes.Buffer
+
+	args := make([]string, 0, len(ce.Arguments))
+	for _, a := range ce.Arguments {
+		args = append(args, a.String())
+	}
+
+	out.WriteString(ce.Function.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// PipeExpression is a pipeline a 🤗 f(b), also written a |> f(b). It passes
+// Left as the first argument to the function call on the Right, so it stands
+// for f(a, b). The Right may also be a bare function, a 🤗 f stands for f(a).
+type PipeExpression struct {
+	Token token.Token // the token.HUG token
+	Left  Expression
+	Right Expression
+}
+
+func (pe *PipeExpression) expressionNode() {}
+
+func (pe *PipeExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
+// String returns the desugared call, e.g. f(a, b) for a 🤗 f(b).
+func (pe *PipeExpression) String() string {
+	return pe.Call().String()
+}
+
+// Call returns the call expression the pipeline stands for.
+func (pe *PipeExpression) Call() *CallExpression {
+	if call, ok := pe.Right.(*CallExpression); ok {
+		args := append([]Expression{pe.Left}, call.Arguments...)
+		return &CallExpression{Token: call.Token, Function: call.Function, Arguments: args}
+	}
+
+	return &CallExpression{Token: pe.Token, Function: pe.Right, Arguments: []Expression{pe.Left}}
+}
+
+type ArrayLiteral struct {
+	Token    token.Token // the token.LBRACKET token
+	Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode() {}
+
+func (al *ArrayLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+
+func (al *ArrayLiteral) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range al.Elements {
+		elements = append(elements, el.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+type IndexExpression struct {
+	Token token.Token // the token.LBRACKET token
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode() {}
+
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// HashPair is a key-value pair of a HashLiteral.
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+// HashLiteral is a hash literal {k: v, ...}. Pairs keeps the order of the
+// source, so String is deterministic.
+type HashLiteral struct {
+	Token token.Token // the token.LBRACE token
+	Pairs []HashPair
+}
+
+func (hl *HashLiteral) expressionNode() {}
+
+func (hl *HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
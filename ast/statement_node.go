@@ -10,6 +10,7 @@ type LetStatement struct {
 	Token token.Token // the token.LET token
 	Name  *Identifier
 	Value Expression
+	Doc   string // text of the /// comments directly above the statement, or ""
 }
 
 func (s *LetStatement) statementNode() {}
@@ -47,12 +48,36 @@ func (s *ReturnStatement) TokenLiteral() string {
 func (s *ReturnStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(s.TokenLiteral() + " ")
+	out.WriteString(s.TokenLiteral())
 
 	if s.Value != nil {
-		out.WriteString(s.Value.String())
+		out.WriteString(" " + s.Value.String())
 	}
 	out.WriteRune(';')
 
 	return out.String()
 }
+
+// BlockStatement is a sequence of statements in braces.
+type BlockStatement struct {
+	Token      token.Token // the token.LBRACE token
+	Statements []Statement
+}
+
+func (bs *BlockStatement) statementNode() {}
+
+func (bs *BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BlockStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteRune('{')
+	for _, s := range bs.Statements {
+		out.WriteString(s.String())
+	}
+	out.WriteRune('}')
+
+	return out.String()
+}
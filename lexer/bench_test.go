@@ -0,0 +1,79 @@
+package lexer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/token"
+)
+
+// syntheticProgram returns a program of n generated statements that uses
+// most of the token types.
+func syntheticProgram(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "let value_%d = %d + 3.5 * (x_%d - 0x%X) / 2 %% 7;\n", i, i, i, i)
+		fmt.Fprintf(&b, "let ok_%d = a <= b && c >= d || !flag != true == false;\n", i)
+		fmt.Fprintf(&b, "total += value_%d |> scale(%d, \"label %d\\n\") 🤗 log; // note\n", i, i, i)
+		fmt.Fprintf(&b, "let f_%d = fn(x, y) { if x > y { return x; } else { return y; } };\n", i)
+	}
+	return b.String()
+}
+
+func BenchmarkLexer(b *testing.B) {
+	input := syntheticProgram(1000)
+	b.SetBytes(int64(len(input)))
+
+	for b.Loop() {
+		l := lexer.New(input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+	}
+}
+
+// punctuationInput is made of operators and delimiters only.
+var punctuationInput = strings.Repeat("(){}[],:;+-*/%!<>=.<=>=&&||==!=+=-=*=/=|>🤗\n", 1000)
+
+// The NextToken benchmarks measure a single call, so allocs/op is the number
+// of allocations per token.
+
+func BenchmarkNextTokenPunctuation(b *testing.B) {
+	benchmarkNextToken(b, punctuationInput)
+}
+
+func BenchmarkNextTokenIdentifiersAndNumbers(b *testing.B) {
+	benchmarkNextToken(b, strings.Repeat("let value_1 = 42 + 0x_FF * 3.5e-2 - other; ", 1000))
+}
+
+func benchmarkNextToken(b *testing.B, input string) {
+	b.ReportAllocs()
+
+	l := lexer.New(input)
+	for b.Loop() {
+		if l.NextToken().Type == token.EOF {
+			l = lexer.New(input)
+		}
+	}
+}
+
+func TestNextTokenAllocations(t *testing.T) {
+	inputs := map[string]string{
+		"punctuation":             punctuationInput,
+		"identifiers and numbers": "let value_1 = 42 + 0x_FF * 3.5e-2 - other; \"a string\" // comment",
+	}
+
+	for name, input := range inputs {
+		l := lexer.New(input)
+		allocs := testing.AllocsPerRun(100, func() {
+			if l.NextToken().Type == token.EOF {
+				l = lexer.New(input)
+			}
+		})
+
+		if allocs != 0 {
+			t.Errorf("%s: NextToken allocates %v times per call, want 0", name, allocs)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package lexer
+
+// readComment reads a // line comment or a /* */ block comment starting at
+// the current character and returns its source text. Block comments nest.
+// The line comment text does not include the terminating line break.
+// The second result is false if a block comment is not terminated; the error
+// is recorded at the position where the comment starts.
+func (l *Lexer) readComment() (string, bool) {
+	start := l.position()
+
+	l.readChar() // '/'
+	if l.ch == '/' {
+		for !l.atLineBreak() && l.ch != 0 {
+			l.readChar()
+		}
+		return l.slice(start.Offset, l.chPosition), true
+	}
+
+	l.readChar() // '*'
+	depth := 1
+	for depth > 0 {
+		switch {
+		case l.ch == 0:
+			l.errorf(start, "block comment not terminated")
+			return l.slice(start.Offset, l.chPosition), false
+		case l.ch == '/' && l.peekChar() == '*':
+			l.readChar()
+			depth++
+		case l.ch == '*' && l.peekChar() == '/':
+			l.readChar()
+			depth--
+		}
+		l.readChar()
+	}
+
+	return l.slice(start.Offset, l.chPosition), true
+}
+
+// readTrailingTrivia reads the spaces, tabs and line comment, unless it is a
+// doc comment, that follow a token on the same line and returns their source
+// text. The line break and anything after it belong to the leading trivia of
+// the next token.
+func (l *Lexer) readTrailingTrivia() string {
+	start := l.chPosition
+
+	for {
+		switch {
+		case l.ch == ' ' || l.ch == '\t':
+			l.readChar()
+		case l.ch == '/' && l.peekChar() == '/' && !l.keepComments && !l.atDocComment():
+			l.readComment()
+		default:
+			return l.slice(start, l.chPosition)
+		}
+	}
+}
+
+// atDocComment reports whether the current character starts a doc comment: a
+// line comment that starts with exactly three slashes.
+func (l *Lexer) atDocComment() bool {
+	return l.ch == '/' && l.peekChar() == '/' && l.peekByte(1) == '/' && l.peekByte(2) != '/'
+}
@@ -0,0 +1,46 @@
+package lexer
+
+import (
+	"strconv"
+
+	"github.com/antklim/go-inter/token"
+)
+
+// Error describes a malformed piece of input found by the Lexer. The token
+// returned for such input has type token.ILLEGAL.
+type Error struct {
+	Pos    token.Position
+	Reason string
+	Hint   string // how to fix the input, if there is an obvious way
+}
+
+func (e Error) Error() string {
+	if e.Hint != "" {
+		return e.Pos.String() + ": " + e.Reason + " — " + e.Hint
+	}
+	return e.Pos.String() + ": " + e.Reason
+}
+
+// unexpectedChar returns the reason and hint of the error for ch, a character
+// that does not start a token.
+func unexpectedChar(ch rune) (reason, hint string) {
+	switch ch {
+	case '&':
+		return "'&' is not an operator", "use '&&'"
+	case '|':
+		return "'|' is not an operator", "use '||' or '|>'"
+	case '$':
+		return "unexpected '$'", "did you mean to start an identifier?"
+	case '#':
+		return "unexpected '#'", "comments start with //"
+	case '?':
+		return "unexpected '?'", "there is no conditional operator, use if"
+	case '“', '”', '„':
+		return "unexpected " + strconv.QuoteRune(ch), `strings are quoted with '"'`
+	case '‘', '’':
+		return "unexpected " + strconv.QuoteRune(ch), "characters are quoted with \"'\""
+	case '\uFEFF':
+		return "unexpected byte order mark", "a byte order mark is only allowed at the start of the input"
+	}
+	return "unexpected " + strconv.QuoteRune(ch), ""
+}
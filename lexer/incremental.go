@@ -0,0 +1,191 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/antklim/go-inter/token"
+)
+
+// restartLookback is the number of tokens before the one containing an edit
+// that Relex lexes again. The lexer looks one character past the end of a
+// token (and past the trailing trivia in lossless mode), so an edit can change
+// how the preceding tokens are lexed.
+const restartLookback = 3
+
+// Edit describes a change of the source: the bytes between the offsets Start
+// and End of the old source are replaced by Text.
+type Edit struct {
+	Start, End int
+	Text       string
+}
+
+// Change describes the tokens that differ after Relex: the tokens
+// prev[Start:OldEnd] were replaced by toks[Start:NewEnd]. The remaining tokens
+// are equal apart from their positions.
+type Change struct {
+	Start  int
+	OldEnd int
+	NewEnd int
+}
+
+// Relex updates the token stream prev after the edit e and returns the
+// tokens of src, the source after the edit. prev must be the complete stream
+// of the source before the edit, up to and including token.EOF, produced with
+// the same options as opts.
+//
+// Relex lexes src from the nearest safe restart point before the edit and
+// stops as soon as the new tokens line up with the old tokens after the edit.
+// The tokens that follow are reused with shifted positions. The result is the
+// same as lexing src from scratch, except that errors are not collected.
+func Relex(src string, prev []token.Token, e Edit, opts ...Option) ([]token.Token, Change) {
+	if len(prev) == 0 {
+		toks := relexAll(New(src, opts...))
+		return toks, Change{NewEnd: len(toks)}
+	}
+
+	// The last token that starts at or before the edit, then a few more back.
+	r := 0
+	for r+1 < len(prev) && tokenStart(prev[r+1]) <= e.Start {
+		r++
+	}
+	r = restartPoint(prev, max(0, r-restartLookback))
+
+	l := New(src, opts...)
+	if r > 0 {
+		l = newAt(src, prev[r-1], prev[r], opts...)
+	}
+	toks := append([]token.Token(nil), prev[:r]...)
+
+	delta := len(e.Text) - (e.End - e.Start)
+	editEnd := e.Start + len(e.Text) // end of the edited text in src
+	j := r                           // candidate old token to sync with
+	depth := 0                       // interpolations open before prev[j]
+
+	for {
+		tok := l.NextToken()
+		toks = append(toks, tok)
+
+		// After a comment the lexer state still depends on the tokens before
+		// it, so only sync on other tokens.
+		start := tokenStart(tok)
+		if start >= editEnd && !isComment(tok.Type) {
+			for j < len(prev) && tokenStart(prev[j])+delta < start {
+				depth += interpolationDelta(prev[j])
+				j++
+			}
+			if j < len(prev) && tokenStart(prev[j])+delta == start && sameToken(prev[j], tok) &&
+				depth == 0 && len(l.interpolations) == 0 {
+				change := Change{Start: r, OldEnd: j, NewEnd: len(toks) - 1}
+				return append(toks[:len(toks)-1], shift(prev[j:], tok, delta)...), change
+			}
+		}
+
+		if tok.Type == token.EOF {
+			return toks, Change{Start: r, OldEnd: len(prev), NewEnd: len(toks)}
+		}
+	}
+}
+
+// restartPoint returns the last index i <= r of a token in prev where the
+// lexer state follows from the token before it alone, which decides whether a
+// line break is a semicolon. That excludes tokens after comments, inserted
+// semicolons, which depend on more than one token, and tokens inside string
+// interpolations.
+func restartPoint(prev []token.Token, r int) int {
+	best, depth := 0, 0
+	for i := 1; i <= r; i++ {
+		depth += interpolationDelta(prev[i-1])
+		if depth == 0 && !isComment(prev[i-1].Type) && !isInsertedSemicolon(prev[i]) {
+			best = i
+		}
+	}
+	return best
+}
+
+// interpolationDelta returns the change of the number of open string
+// interpolations after tok. A malformed part of a string literal that ends
+// with "${" counts as an opening one. The count can only be too high, after
+// other malformed string literals, which makes Relex restart earlier.
+func interpolationDelta(tok token.Token) int {
+	switch {
+	case tok.Type == token.STRING_START:
+		return 1
+	case tok.Type == token.STRING_END:
+		return -1
+	case tok.Type == token.ILLEGAL && strings.HasSuffix(tok.Literal, "${"):
+		return 1
+	}
+	return 0
+}
+
+// newAt returns a Lexer over src that starts lexing at tok, which must follow
+// the complete token before in src.
+func newAt(src string, before, tok token.Token, opts ...Option) *Lexer {
+	start := tokenStart(tok)
+
+	l := newLexer(opts)
+	l.input = src
+	l.nextChPosition = start
+	l.line = tok.Pos.Line - lineBreaks(tok.Leading)
+	l.lineStart = strings.LastIndexAny(src[:start], "\n\r") + 1
+	l.insertSemi = endsStatement(before.Type)
+	l.readChar()
+
+	return l
+}
+
+// lineBreaks returns the number of line breaks in s, where \r\n counts as one.
+func lineBreaks(s string) int {
+	return strings.Count(s, "\n") + strings.Count(s, "\r") - strings.Count(s, "\r\n")
+}
+
+func relexAll(l *Lexer) []token.Token {
+	var toks []token.Token
+	for {
+		tok := l.NextToken()
+		toks = append(toks, tok)
+		if tok.Type == token.EOF {
+			return toks
+		}
+	}
+}
+
+// tokenStart returns the offset where the source text of tok, including its
+// leading trivia, starts.
+func tokenStart(tok token.Token) int {
+	return tok.Pos.Offset - len(tok.Leading)
+}
+
+// isInsertedSemicolon reports whether tok is a semicolon inserted at a line
+// break or the end of the input.
+func isInsertedSemicolon(tok token.Token) bool {
+	return tok.Type == token.SEMICOLON && tok.Literal == "\n"
+}
+
+func sameToken(a, b token.Token) bool {
+	return a.Type == b.Type &&
+		a.Literal == b.Literal &&
+		a.Leading == b.Leading &&
+		a.Text == b.Text &&
+		a.Trailing == b.Trailing
+}
+
+// shift returns a copy of old with positions moved so that old[0] is at the
+// position of tok, the equal token lexed from the edited source.
+func shift(old []token.Token, tok token.Token, delta int) []token.Token {
+	lineDelta := tok.Pos.Line - old[0].Pos.Line
+	columnDelta := tok.Pos.Column - old[0].Pos.Column
+	line := old[0].Pos.Line
+
+	out := make([]token.Token, len(old))
+	for i, t := range old {
+		if t.Pos.Line == line {
+			t.Pos.Column += columnDelta
+		}
+		t.Pos.Offset += delta
+		t.Pos.Line += lineDelta
+		out[i] = t
+	}
+
+	return out
+}
@@ -0,0 +1,87 @@
+package lexer_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/token"
+)
+
+func TestRelex(t *testing.T) {
+	t.Run("reports the changed range", func(t *testing.T) {
+		src := "let x = 5;\nlet y = 10;\nlet z = x + y;\n"
+		prev, _ := tokenize(lexer.New(src))
+
+		// let y = 10; -> let y = 1000;
+		e := lexer.Edit{Start: 19, End: 21, Text: "1000"}
+		newSrc := src[:e.Start] + e.Text + src[e.End:]
+
+		got, change := lexer.Relex(newSrc, prev, e)
+		want, _ := tokenize(lexer.New(newSrc))
+
+		assertTokens(t, want, got)
+
+		// Lexing restarts three tokens before 10 and syncs at the semicolon.
+		if want := (lexer.Change{Start: 5, OldEnd: 9, NewEnd: 9}); change != want {
+			t.Errorf("wrong change: want %+v, got %+v", want, change)
+		}
+	})
+
+	t.Run("matches a full lex for random edits", func(t *testing.T) {
+		snippets := []string{
+			"", " ", "\n", "\r\n", "\r", "\xff", "\uFEFF", "#!", "x", "let", "=", "==", "!", "5", ".5", "e", "0x", "_",
+			"\"", "\\", "\"str\"", "'", "'a'", "${", "\"a${", "`", "\\q", "/", "//", "/*", "*/", "&", "|", "🤗", "é", "\u0301", "(", "}", ";", "[", "]", ":",
+		}
+
+		for _, input := range append(readerCorpus, assignmentsInput, ifStatementsInput, commentsInput) {
+			rnd := rand.New(rand.NewPCG(1, uint64(len(input))))
+
+			for _, opts := range [][]lexer.Option{
+				nil,
+				{lexer.WithComments()},
+				{lexer.WithTrivia()},
+				{lexer.WithTrivia(), lexer.WithComments(), lexer.WithFilename("a.gi")},
+			} {
+				src := input
+				prev, _ := tokenize(lexer.New(src, opts...))
+
+				for range 200 {
+					start := rnd.IntN(len(src) + 1)
+					end := start + rnd.IntN(min(4, len(src)-start)+1)
+					e := lexer.Edit{Start: start, End: end, Text: snippets[rnd.IntN(len(snippets))]}
+
+					newSrc := src[:e.Start] + e.Text + src[e.End:]
+					got, change := lexer.Relex(newSrc, prev, e, opts...)
+					want, _ := tokenize(lexer.New(newSrc, opts...))
+
+					if !assertTokens(t, want, got) {
+						t.Fatalf("edit %+v of %q", e, src)
+					}
+					if change.Start > change.OldEnd || change.Start > change.NewEnd ||
+						len(prev)-change.OldEnd != len(got)-change.NewEnd {
+						t.Fatalf("edit %+v of %q: inconsistent change %+v", e, src, change)
+					}
+
+					src, prev = newSrc, got
+				}
+			}
+		}
+	})
+}
+
+func assertTokens(t *testing.T, want, got []token.Token) bool {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Errorf("want %d tokens, got %d", len(want), len(got))
+		return false
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("token #%d:\n\twant %+v\n\t got %+v", i, want[i], got[i])
+			return false
+		}
+	}
+	return true
+}
@@ -1,28 +1,129 @@
 package lexer
 
 import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/antklim/go-inter/token"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Lexer struct {
-	input          string
+	input          string // the source, or the buffered part of it when reading from an io.Reader
+	base           int    // offset of input[0] in the source
+	mark           int    // offset of the first character that must stay buffered
+	reader         io.Reader
+	buf            []byte // read buffer, nil unless the Lexer reads from an io.Reader
+	bufSize        int    // size of buf, set by WithBufferSize
+	filename       string
+	dialect        *token.Dialect
 	ch             rune
-	chPosition     int // referenced as position in book
-	nextChPosition int // referenced as readPosition in book
+	chPosition     int             // referenced as position in book
+	nextChPosition int             // referenced as readPosition in book
+	line           int             // line number of ch, starting at 1
+	lineStart      int             // offset of the first character of the current line
+	keepComments   bool            // emit token.COMMENT tokens instead of skipping comments
+	lossless       bool            // attach trivia and source text to tokens
+	insertSemi     bool            // insert a semicolon before the next line break or EOF
+	newline        bool            // a comment read since the last token spans a line break
+	interpolations []interpolation // mode stack of open ${...} in string literals, innermost last
+	errors         []Error
 }
 
-func New(input string) *Lexer {
-	l := &Lexer{input: input}
+func New(input string, opts ...Option) *Lexer {
+	l := newLexer(opts)
+	l.input = input
 	l.readChar()
 	return l
 }
 
+// newLexer returns a Lexer with the default settings and opts applied, which
+// is not yet positioned at the first character.
+func newLexer(opts []Option) *Lexer {
+	l := &Lexer{line: 1, dialect: token.Default, bufSize: DefaultBufferSize}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
 func (l *Lexer) NextToken() token.Token {
+	if !l.lossless {
+		return l.nextToken()
+	}
+
+	start := l.chPosition
+	l.mark = start
+
+	tok := l.nextToken()
+	tok.Leading = l.slice(start, tok.Pos.Offset)
+	tok.Text = l.slice(tok.Pos.Offset, l.chPosition)
+	tok.Trailing = l.readTrailingTrivia()
+
+	return tok
+}
+
+// nextToken returns the next token and records whether a line break after it
+// ends a statement.
+func (l *Lexer) nextToken() token.Token {
+	tok := l.scan()
+	if !isComment(tok.Type) {
+		// Line breaks inside an interpolation do not end statements.
+		l.insertSemi = endsStatement(tok.Type) && len(l.interpolations) == 0
+		l.newline = false
+	}
+	return tok
+}
+
+func (l *Lexer) scan() token.Token {
 	var tok token.Token
 
+	if l.chPosition == 0 {
+		l.skipPrologue()
+	}
 	l.skipWhitespace()
+	for l.ch == '/' && (l.peekChar() == '/' || l.peekChar() == '*') {
+		l.setMark()
+		pos := l.position()
+		doc := l.atDocComment()
+		lit, ok := l.readComment()
+		if !ok {
+			tok = newToken(token.ILLEGAL, lit)
+			tok.Pos = pos
+			return tok
+		}
+		if doc {
+			tok = newToken(token.DOC_COMMENT, lit)
+			tok.Pos = pos
+			return tok
+		}
+		if l.insertSemi && strings.ContainsAny(lit, "\n\r") {
+			l.newline = true
+		}
+		if l.keepComments {
+			tok = newToken(token.COMMENT, lit)
+			tok.Pos = pos
+			return tok
+		}
+		if l.newline {
+			break
+		}
+		l.skipWhitespace()
+	}
+	l.setMark()
+	pos := l.position()
+
+	if l.insertSemi && (l.newline || l.atLineBreak() || l.ch == 0) {
+		// Like Go, a line break or the end of the input after a token that
+		// can end a statement is a semicolon. The semicolon has no source
+		// text, the line break stays in the leading trivia of the next token.
+		tok = newToken(token.SEMICOLON, "\n")
+		tok.Pos = pos
+		return tok
+	}
 
 	switch l.ch {
 	case '=':
@@ -30,89 +131,288 @@ func (l *Lexer) NextToken() token.Token {
 			l.readChar()
 			tok = newToken(token.EQ, "==")
 		} else {
-			tok = newRuneToken(token.ASSIGN, l.ch)
+			tok = newToken(token.ASSIGN, "=")
 		}
 	case '+':
-		tok = newRuneToken(token.PLUS, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.PLUS_ASSIGN, "+=")
+		} else {
+			tok = newToken(token.PLUS, "+")
+		}
 	case '-':
-		tok = newRuneToken(token.MINUS, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.MINUS_ASSIGN, "-=")
+		} else {
+			tok = newToken(token.MINUS, "-")
+		}
 	case '*':
-		tok = newRuneToken(token.ASTERISK, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.ASTERISK_ASSIGN, "*=")
+		} else {
+			tok = newToken(token.ASTERISK, "*")
+		}
+	case '%':
+		tok = newToken(token.PERCENT, "%")
 	case '.':
-		tok = newRuneToken(token.PERIOD, l.ch)
+		if isDigit(rune(l.peekChar())) {
+			tok = newToken(l.readNumber())
+			tok.Pos = pos
+			return tok
+		}
+		tok = newToken(token.PERIOD, ".")
 	case '!':
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = newToken(token.NOT_EQ, "!=")
 		} else {
-			tok = newRuneToken(token.BANG, l.ch)
+			tok = newToken(token.BANG, "!")
 		}
 	case ',':
-		tok = newRuneToken(token.COMMA, l.ch)
+		tok = newToken(token.COMMA, ",")
 	case ';':
-		tok = newRuneToken(token.SEMICOLON, l.ch)
+		tok = newToken(token.SEMICOLON, ";")
 	case '/':
-		tok = newRuneToken(token.SLASH, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.SLASH_ASSIGN, "/=")
+		} else {
+			tok = newToken(token.SLASH, "/")
+		}
 	case '(':
-		tok = newRuneToken(token.LPAREN, l.ch)
+		tok = newToken(token.LPAREN, "(")
 	case ')':
-		tok = newRuneToken(token.RPAREN, l.ch)
+		tok = newToken(token.RPAREN, ")")
 	case '{':
-		tok = newRuneToken(token.LBRACE, l.ch)
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1].braces++
+		}
+		tok = newToken(token.LBRACE, "{")
 	case '}':
-		tok = newRuneToken(token.RBRACE, l.ch)
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1].braces == 0 {
+				tok = newToken(l.readStringLiteral())
+				tok.Pos = pos
+				return tok
+			}
+			l.interpolations[n-1].braces--
+		}
+		tok = newToken(token.RBRACE, "}")
+	case '[':
+		tok = newToken(token.LBRACKET, "[")
+	case ']':
+		tok = newToken(token.RBRACKET, "]")
+	case ':':
+		tok = newToken(token.COLON, ":")
 	case '<':
-		tok = newRuneToken(token.LT, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.LT_EQ, "<=")
+		} else {
+			tok = newToken(token.LT, "<")
+		}
 	case '>':
-		tok = newRuneToken(token.GT, l.ch)
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = newToken(token.GT_EQ, ">=")
+		} else {
+			tok = newToken(token.GT, ">")
+		}
+	case '&':
+		if l.peekChar() == '&' {
+			l.readChar()
+			tok = newToken(token.AND, "&&")
+		} else {
+			tok = l.illegalChar(pos)
+		}
+	case '|':
+		if l.peekChar() == '|' {
+			l.readChar()
+			tok = newToken(token.OR, "||")
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = newToken(token.HUG, "|>")
+		} else {
+			tok = l.illegalChar(pos)
+		}
 	case '🤗':
-		tok = newRuneToken(token.HUG, l.ch)
+		tok = newToken(token.HUG, "🤗")
+	case '"':
+		tok = newToken(l.readStringLiteral())
+		tok.Pos = pos
+		return tok
+	case '`':
+		lit, ok := l.readRawString()
+		if ok {
+			tok = newToken(token.STRING, lit)
+		} else {
+			tok = newToken(token.ILLEGAL, lit)
+		}
+		tok.Pos = pos
+		return tok
+	case '\'':
+		lit, ok := l.readCharLiteral()
+		if ok {
+			tok = newToken(token.CHAR, lit)
+		} else {
+			tok = newToken(token.ILLEGAL, lit)
+		}
+		tok.Pos = pos
+		return tok
 	case 0:
-		tok = newRuneToken(token.EOF, l.ch)
+		if len(l.interpolations) > 0 {
+			l.errorf(l.interpolations[0].start, "string literal not terminated")
+			l.interpolations = nil
+		}
+		tok = newToken(token.EOF, "\x00")
 	default:
 		if isLetter(l.ch) {
-			l := l.readIdentifier()
-			t := token.LookupIdent(l)
-			return newToken(t, l)
+			lit := l.readIdentifier()
+			tok = newToken(l.dialect.LookupIdent(lit), lit)
+			tok.Pos = pos
+			return tok
 		} else if isDigit(l.ch) {
-			l := l.readNumber()
-			tok = newToken(token.INT, l)
+			tok = newToken(l.readNumber())
+			tok.Pos = pos
 			return tok
+		} else if l.invalidUTF8() {
+			l.errorf(pos, "invalid UTF-8 encoding")
+			tok = newToken(token.ILLEGAL, l.slice(l.chPosition, l.nextChPosition))
 		} else {
-			tok = newRuneToken(token.ILLEGAL, l.ch)
+			tok = l.illegalChar(pos)
 		}
 	}
 
+	if token.IsOperator(tok.Type) && !l.dialect.IsOperator(tok.Type) {
+		l.errorf(pos, "operator %s is not supported in this dialect", tok.Literal)
+		tok.Type = token.ILLEGAL
+	}
+
 	l.readChar()
+	tok.Pos = pos
 	return tok
 }
 
+// Dialect returns the dialect the Lexer recognises.
+func (l *Lexer) Dialect() *token.Dialect {
+	return l.dialect
+}
+
+// Errors returns the errors found so far in the input.
+func (l *Lexer) Errors() []Error {
+	return l.errors
+}
+
+func (l *Lexer) errorf(pos token.Position, format string, args ...any) {
+	l.errors = append(l.errors, Error{Pos: pos, Reason: fmt.Sprintf(format, args...)})
+}
+
+// illegalChar records an error for the unexpected current character at pos
+// and returns a token.ILLEGAL token for it.
+func (l *Lexer) illegalChar(pos token.Position) token.Token {
+	reason, hint := unexpectedChar(l.ch)
+	l.errors = append(l.errors, Error{Pos: pos, Reason: reason, Hint: hint})
+	return newRuneToken(token.ILLEGAL, l.ch)
+}
+
+// position returns the source position of the current character.
+func (l *Lexer) position() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.chPosition,
+		Line:     l.line,
+		Column:   l.chPosition - l.lineStart + 1,
+	}
+}
+
+// setMark marks the current character as the start of the text that must
+// stay buffered. In lossless mode the mark stays at the start of the leading
+// trivia.
+func (l *Lexer) setMark() {
+	if !l.lossless {
+		l.mark = l.chPosition
+	}
+}
+
+// slice returns the source text between the offsets start and end. Text read
+// from an io.Reader is copied, so tokens do not keep the read buffer alive.
+func (l *Lexer) slice(start, end int) string {
+	s := l.input[start-l.base : end-l.base]
+	if l.buf != nil {
+		return strings.Clone(s)
+	}
+	return s
+}
+
 func (l *Lexer) peekChar() byte {
-	if l.nextChPosition >= len(l.input) {
+	return l.peekByte(0)
+}
+
+// peekByte returns the byte n bytes after the one peekChar returns, or 0 at
+// the end of the input.
+func (l *Lexer) peekByte(n int) byte {
+	if l.reader != nil {
+		l.fill(l.nextChPosition + n + 1)
+	}
+
+	i := l.nextChPosition + n - l.base
+	if i >= len(l.input) {
 		return 0
 	}
 
-	return l.input[l.nextChPosition]
+	return l.input[i]
 }
 
 func (l *Lexer) readChar() {
+	if l.reader != nil {
+		l.fill(l.nextChPosition + utf8.UTFMax)
+	}
+
+	prev := l.ch
+
 	var w int
-	if l.nextChPosition >= len(l.input) {
+	if i := l.nextChPosition - l.base; i >= len(l.input) {
 		l.ch = 0
 	} else {
-		l.ch, w = utf8.DecodeRuneInString(l.input[l.nextChPosition:])
+		l.ch, w = utf8.DecodeRuneInString(l.input[i:])
 	}
 
 	l.chPosition = l.nextChPosition
 	l.nextChPosition += w
+
+	// \n, \r\n and a lone \r are line breaks.
+	if prev == '\n' || prev == '\r' && l.ch != '\n' {
+		l.line++
+		l.lineStart = l.chPosition
+	}
 }
 
-func (l *Lexer) readIdentifier() string {
-	return l.readString(isLetter)
+// skipPrologue skips a byte order mark and a #! line at the start of the
+// input, so that scripts and files saved by Windows editors lex cleanly. The
+// line break after the #! line is left to the whitespace.
+func (l *Lexer) skipPrologue() {
+	if l.ch == '\uFEFF' {
+		l.readChar()
+	}
+	if l.ch == '#' && l.peekChar() == '!' {
+		for !l.atLineBreak() && l.ch != 0 {
+			l.readChar()
+		}
+	}
 }
 
-func (l *Lexer) readNumber() string {
-	return l.readString(isDigit)
+// invalidUTF8 reports whether the current character is a byte that is not
+// valid UTF-8, as opposed to an encoded U+FFFD.
+func (l *Lexer) invalidUTF8() bool {
+	return l.ch == utf8.RuneError && l.nextChPosition-l.chPosition == 1
+}
+
+// readIdentifier reads an identifier and returns it in Unicode normalization
+// form C, so that canonically equivalent spellings of a name are equal.
+func (l *Lexer) readIdentifier() string {
+	return norm.NFC.String(l.readString(isIdentifierChar))
 }
 
 // readString reads and returns string as long predicate function f returns true.
@@ -121,19 +421,57 @@ func (l *Lexer) readString(f func(rune) bool) string {
 	for f(l.ch) {
 		l.readChar()
 	}
-	return l.input[position:l.chPosition]
+	return l.slice(position, l.chPosition)
 }
 
+// skipWhitespace skips whitespace up to the next token, or up to a line break
+// that is an automatic semicolon.
 func (l *Lexer) skipWhitespace() {
-	for isWhitespace(l.ch) {
+	for isWhitespace(l.ch) && !(l.insertSemi && l.atLineBreak()) {
 		l.readChar()
 	}
 }
 
+// atLineBreak reports whether the current character starts a line break:
+// \n, \r\n or a lone \r.
+func (l *Lexer) atLineBreak() bool {
+	return l.ch == '\n' || l.ch == '\r'
+}
+
+// isLetter reports whether ch can start an identifier.
 func isLetter(ch rune) bool {
-	return 'a' <= ch && ch <= 'z' ||
-		'A' <= ch && ch <= 'Z' ||
-		ch == '_'
+	if ch < utf8.RuneSelf {
+		return 'a' <= ch && ch <= 'z' ||
+			'A' <= ch && ch <= 'Z' ||
+			ch == '_'
+	}
+	return unicode.IsLetter(ch)
+}
+
+// isIdentifierChar reports whether ch can continue an identifier. Besides
+// letters and digits it accepts combining marks, which appear in names that
+// are not in normalization form C.
+func isIdentifierChar(ch rune) bool {
+	if ch < utf8.RuneSelf {
+		return isLetter(ch) || isDigit(ch)
+	}
+	return unicode.In(ch, unicode.Letter, unicode.Digit, unicode.Mn, unicode.Mc)
+}
+
+// isComment reports whether t is the type of a comment token.
+func isComment(t token.TokenType) bool {
+	return t == token.COMMENT || t == token.DOC_COMMENT
+}
+
+// endsStatement reports whether a token of type t followed by a line break
+// ends a statement.
+func endsStatement(t token.TokenType) bool {
+	switch t {
+	case token.IDENT, token.INT, token.FLOAT, token.STRING, token.CHAR, token.STRING_END, token.TRUE, token.FALSE,
+		token.RPAREN, token.RBRACE, token.RBRACKET, token.RETURN:
+		return true
+	}
+	return false
 }
 
 func isDigit(ch rune) bool {
@@ -7,9 +7,55 @@ import (
 	"github.com/antklim/go-inter/token"
 )
 
+// Inputs shared by the tokenisation tests and the lossless round-trip test.
+const (
+	singleCharactersInput = "=+{}(),🤗;!-/ *5 < 10 > 8.,"
+
+	singleIdentifierInput = "test"
+
+	assignmentsInput = `let five = 5;
+		let ten = 10;
+
+		let add = fn(x, y) {
+			x + y;
+		};
+
+		let sub = fn(x, y) {
+			return x - y;
+		};
+
+		let add_result = add(five, ten);
+		let sub_result = sub(7, 1);
+		`
+
+	ifStatementsInput = `
+		let gt = fn(x, y) {
+			if x > y {
+				return true;
+			} else {
+				return false;
+			}
+		}
+
+		let negative = fn(x) {
+			x < 0
+		}
+		`
+
+	equalityInput = `
+		10 == 10;
+		10 != 8;
+		`
+
+	commentsInput = `// leading comment
+let x = 5; // trailing comment
+/* block /* nested */ still comment */ x / y
+/**/x`
+)
+
 func TestNextToken(t *testing.T) {
 	t.Run("single characters tokenisation", func(t *testing.T) {
-		input := "=+{}(),🤗;!-/*5 < 10 > 8.,"
+		input := singleCharactersInput
 
 		testCases := []struct {
 			expectedType    token.TokenType
@@ -53,7 +99,7 @@ func TestNextToken(t *testing.T) {
 	})
 
 	t.Run("single identifier", func(t *testing.T) {
-		input := "test"
+		input := singleIdentifierInput
 
 		testCases := []struct {
 			expectedType    token.TokenType
@@ -78,20 +124,7 @@ func TestNextToken(t *testing.T) {
 	})
 
 	t.Run("assignments and functions", func(t *testing.T) {
-		input := `let five = 5;
-		let ten = 10;
-
-		let add = fn(x, y) {
-			x + y;
-		};
-
-		let sub = fn(x, y) {
-			return x - y;
-		};
-
-		let add_result = add(five, ten);
-		let sub_result = sub(7, 1);
-		`
+		input := assignmentsInput
 
 		testCases := []struct {
 			expectedType    token.TokenType
@@ -185,19 +218,7 @@ func TestNextToken(t *testing.T) {
 	})
 
 	t.Run("if statements", func(t *testing.T) {
-		input := `
-		let gt = fn(x, y) {
-			if x > y {
-				return true;
-			} else {
-				return false;
-			}
-		}
-
-		let negative = fn(x) {
-			x < 0
-		}
-		`
+		input := ifStatementsInput
 
 		testCases := []struct {
 			expectedType    token.TokenType
@@ -228,7 +249,9 @@ func TestNextToken(t *testing.T) {
 			{expectedType: token.FALSE, expectedLiteral: "false"},
 			{expectedType: token.SEMICOLON, expectedLiteral: ";"},
 			{expectedType: token.RBRACE, expectedLiteral: "}"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
 			{expectedType: token.RBRACE, expectedLiteral: "}"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
 
 			{expectedType: token.LET, expectedLiteral: "let"},
 			{expectedType: token.IDENT, expectedLiteral: "negative"},
@@ -241,7 +264,9 @@ func TestNextToken(t *testing.T) {
 			{expectedType: token.IDENT, expectedLiteral: "x"},
 			{expectedType: token.LT, expectedLiteral: "<"},
 			{expectedType: token.INT, expectedLiteral: "0"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
 			{expectedType: token.RBRACE, expectedLiteral: "}"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
 
 			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
 		}
@@ -262,10 +287,7 @@ func TestNextToken(t *testing.T) {
 	})
 
 	t.Run("equality tokens", func(t *testing.T) {
-		input := `
-		10 == 10;
-		10 != 8;
-		`
+		input := equalityInput
 
 		testCases := []struct {
 			expectedType    token.TokenType
@@ -296,4 +318,1022 @@ func TestNextToken(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("collections", func(t *testing.T) {
+		input := "[1, 2][0]; {\"k\": v}\nxs[i]\n"
+
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+		}{
+			{expectedType: token.LBRACKET, expectedLiteral: "["},
+			{expectedType: token.INT, expectedLiteral: "1"},
+			{expectedType: token.COMMA, expectedLiteral: ","},
+			{expectedType: token.INT, expectedLiteral: "2"},
+			{expectedType: token.RBRACKET, expectedLiteral: "]"},
+			{expectedType: token.LBRACKET, expectedLiteral: "["},
+			{expectedType: token.INT, expectedLiteral: "0"},
+			{expectedType: token.RBRACKET, expectedLiteral: "]"},
+			{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+			{expectedType: token.LBRACE, expectedLiteral: "{"},
+			{expectedType: token.STRING, expectedLiteral: `"k"`},
+			{expectedType: token.COLON, expectedLiteral: ":"},
+			{expectedType: token.IDENT, expectedLiteral: "v"},
+			{expectedType: token.RBRACE, expectedLiteral: "}"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.IDENT, expectedLiteral: "xs"},
+			{expectedType: token.LBRACKET, expectedLiteral: "["},
+			{expectedType: token.IDENT, expectedLiteral: "i"},
+			{expectedType: token.RBRACKET, expectedLiteral: "]"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+		}
+
+		l := lexer.New(input)
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+		}
+	})
+}
+
+func TestOperators(t *testing.T) {
+	input := "<= >= && || % += -= *= /= |> < > & |"
+
+	testCases := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{expectedType: token.LT_EQ, expectedLiteral: "<="},
+		{expectedType: token.GT_EQ, expectedLiteral: ">="},
+		{expectedType: token.AND, expectedLiteral: "&&"},
+		{expectedType: token.OR, expectedLiteral: "||"},
+		{expectedType: token.PERCENT, expectedLiteral: "%"},
+		{expectedType: token.PLUS_ASSIGN, expectedLiteral: "+="},
+		{expectedType: token.MINUS_ASSIGN, expectedLiteral: "-="},
+		{expectedType: token.ASTERISK_ASSIGN, expectedLiteral: "*="},
+		{expectedType: token.SLASH_ASSIGN, expectedLiteral: "/="},
+		{expectedType: token.HUG, expectedLiteral: "|>"},
+		{expectedType: token.LT, expectedLiteral: "<"},
+		{expectedType: token.GT, expectedLiteral: ">"},
+		{expectedType: token.ILLEGAL, expectedLiteral: "&"},
+		{expectedType: token.ILLEGAL, expectedLiteral: "|"},
+		{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+	}
+
+	l := lexer.New(input)
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tC.expectedLiteral {
+			t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPosition(t *testing.T) {
+	input := "let x = 5;\n  x 🤗 y\n"
+
+	testCases := []struct {
+		expectedType token.TokenType
+		expectedPos  token.Position
+	}{
+		{expectedType: token.LET, expectedPos: token.Position{Filename: "main.gi", Offset: 0, Line: 1, Column: 1}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Filename: "main.gi", Offset: 4, Line: 1, Column: 5}},
+		{expectedType: token.ASSIGN, expectedPos: token.Position{Filename: "main.gi", Offset: 6, Line: 1, Column: 7}},
+		{expectedType: token.INT, expectedPos: token.Position{Filename: "main.gi", Offset: 8, Line: 1, Column: 9}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Filename: "main.gi", Offset: 9, Line: 1, Column: 10}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Filename: "main.gi", Offset: 13, Line: 2, Column: 3}},
+		{expectedType: token.HUG, expectedPos: token.Position{Filename: "main.gi", Offset: 15, Line: 2, Column: 5}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Filename: "main.gi", Offset: 20, Line: 2, Column: 10}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Filename: "main.gi", Offset: 21, Line: 2, Column: 11}},
+		{expectedType: token.EOF, expectedPos: token.Position{Filename: "main.gi", Offset: 22, Line: 3, Column: 1}},
+	}
+
+	l := lexer.New(input, lexer.WithFilename("main.gi"))
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Pos != tC.expectedPos {
+			t.Errorf("test #%d wrong position: want %+v, got %+v", i, tC.expectedPos, tok.Pos)
+		}
+	}
+}
+
+func TestStringLiteral(t *testing.T) {
+	t.Run("valid literals", func(t *testing.T) {
+		input := `"foobar" "foo bar" "a\tb\n" "say \"hi\"" "back\\slash" "\u{1F917}" ""`
+
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+			expectedValue   string
+		}{
+			{expectedType: token.STRING, expectedLiteral: `"foobar"`, expectedValue: "foobar"},
+			{expectedType: token.STRING, expectedLiteral: `"foo bar"`, expectedValue: "foo bar"},
+			{expectedType: token.STRING, expectedLiteral: `"a\tb\n"`, expectedValue: "a\tb\n"},
+			{expectedType: token.STRING, expectedLiteral: `"say \"hi\""`, expectedValue: `say "hi"`},
+			{expectedType: token.STRING, expectedLiteral: `"back\\slash"`, expectedValue: `back\slash`},
+			{expectedType: token.STRING, expectedLiteral: `"\u{1F917}"`, expectedValue: "🤗"},
+			{expectedType: token.STRING, expectedLiteral: `""`, expectedValue: ""},
+		}
+
+		l := lexer.New(input)
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+
+			value, err := lexer.Unquote(tok.Literal)
+			if err != nil {
+				t.Errorf("test #%d unexpected unquote error: %v", i, err)
+			}
+			if value != tC.expectedValue {
+				t.Errorf("test #%d wrong value: want %q, got %q", i, tC.expectedValue, value)
+			}
+		}
+
+		if errs := l.Errors(); len(errs) != 0 {
+			t.Errorf("unexpected lexer errors: %v", errs)
+		}
+	})
+
+	t.Run("malformed literals", func(t *testing.T) {
+		testCases := []struct {
+			input           string
+			expectedLiteral string
+			expectedError   string
+		}{
+			{input: `"abc`, expectedLiteral: `"abc`, expectedError: "1:1: string literal not terminated"},
+			{input: "\"abc\nd\"", expectedLiteral: `"abc`, expectedError: "1:1: string literal not terminated"},
+			{input: `x = "a\qb"`, expectedLiteral: `"a\qb"`, expectedError: `1:7: unknown escape sequence \q`},
+			{input: `"\u12"`, expectedLiteral: `"\u12"`, expectedError: `1:2: invalid escape sequence: missing '{' after \u`},
+			{input: `"\u{12"`, expectedLiteral: `"\u{12"`, expectedError: `1:2: invalid escape sequence: missing '}' in \u{12`},
+			{input: `"\u{}"`, expectedLiteral: `"\u{}"`, expectedError: `1:2: invalid escape sequence \u{}: expected 1 to 6 hexadecimal digits`},
+			{input: `"\u{D800}"`, expectedLiteral: `"\u{D800}"`, expectedError: `1:2: invalid escape sequence \u{D800}: escape is not a valid Unicode code point`},
+			{input: "`raw\nstring", expectedLiteral: "`raw\nstring", expectedError: "1:1: raw string literal not terminated"},
+			{input: "x = \"a ${b}\nc\"", expectedLiteral: "}", expectedError: "1:5: string literal not terminated"},
+			{input: `"a ${"b ${c}`, expectedLiteral: "}", expectedError: "1:6: string literal not terminated"},
+		}
+
+		for i, tC := range testCases {
+			l := lexer.New(tC.input)
+
+			tok := l.NextToken()
+			for tok.Type != token.ILLEGAL && tok.Type != token.EOF {
+				tok = l.NextToken()
+			}
+
+			if tok.Type != token.ILLEGAL {
+				t.Fatalf("test #%d wrong token type: want %q, got %q", i, token.ILLEGAL, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+
+			errs := l.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("test #%d want 1 lexer error, got %d: %v", i, len(errs), errs)
+			}
+			if errs[0].Error() != tC.expectedError {
+				t.Errorf("test #%d wrong error: want %q, got %q", i, tC.expectedError, errs[0].Error())
+			}
+		}
+	})
+}
+
+func TestRawStringLiteral(t *testing.T) {
+	input := "`first\r\n\"second\" \\n ${x}` ``"
+
+	testCases := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedValue   string
+	}{
+		{expectedType: token.STRING, expectedLiteral: "`first\r\n\"second\" \\n ${x}`", expectedValue: "first\n\"second\" \\n ${x}"},
+		{expectedType: token.STRING, expectedLiteral: "``", expectedValue: ""},
+	}
+
+	l := lexer.New(input)
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tC.expectedLiteral {
+			t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+		}
+
+		value, err := lexer.Unquote(tok.Literal)
+		if err != nil {
+			t.Errorf("test #%d unexpected unquote error: %v", i, err)
+		}
+		if value != tC.expectedValue {
+			t.Errorf("test #%d wrong value: want %q, got %q", i, tC.expectedValue, value)
+		}
+	}
+
+	if tok := l.NextToken(); tok.Pos.Line != 2 {
+		t.Errorf("wrong line after a multi-line raw string: want 2, got %d", tok.Pos.Line)
+	}
+}
+
+func TestInterpolatedString(t *testing.T) {
+	input := `"Hello ${name}!" "${a}${ {b} }" "\${x} $y" "${"in ${s}"}"` + "\n"
+
+	testCases := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedValue   string
+	}{
+		{expectedType: token.STRING_START, expectedLiteral: `"Hello ${`, expectedValue: "Hello "},
+		{expectedType: token.IDENT, expectedLiteral: "name"},
+		{expectedType: token.STRING_END, expectedLiteral: `}!"`, expectedValue: "!"},
+
+		{expectedType: token.STRING_START, expectedLiteral: `"${`, expectedValue: ""},
+		{expectedType: token.IDENT, expectedLiteral: "a"},
+		{expectedType: token.STRING_MID, expectedLiteral: `}${`, expectedValue: ""},
+		{expectedType: token.LBRACE, expectedLiteral: "{"},
+		{expectedType: token.IDENT, expectedLiteral: "b"},
+		{expectedType: token.RBRACE, expectedLiteral: "}"},
+		{expectedType: token.STRING_END, expectedLiteral: `}"`, expectedValue: ""},
+
+		{expectedType: token.STRING, expectedLiteral: `"\${x} $y"`, expectedValue: "${x} $y"},
+
+		{expectedType: token.STRING_START, expectedLiteral: `"${`, expectedValue: ""},
+		{expectedType: token.STRING_START, expectedLiteral: `"in ${`, expectedValue: "in "},
+		{expectedType: token.IDENT, expectedLiteral: "s"},
+		{expectedType: token.STRING_END, expectedLiteral: `}"`, expectedValue: ""},
+		{expectedType: token.STRING_END, expectedLiteral: `}"`, expectedValue: ""},
+
+		{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+		{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+	}
+
+	l := lexer.New(input)
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tC.expectedLiteral {
+			t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+		}
+
+		switch tok.Type {
+		case token.STRING, token.STRING_START, token.STRING_MID, token.STRING_END:
+			value, err := lexer.Unquote(tok.Literal)
+			if err != nil {
+				t.Errorf("test #%d unexpected unquote error: %v", i, err)
+			}
+			if value != tC.expectedValue {
+				t.Errorf("test #%d wrong value: want %q, got %q", i, tC.expectedValue, value)
+			}
+		}
+	}
+
+	if errs := l.Errors(); len(errs) != 0 {
+		t.Errorf("unexpected lexer errors: %v", errs)
+	}
+
+	t.Run("unterminated interpolation", func(t *testing.T) {
+		l := lexer.New(`x = "a ${b + {c}`)
+
+		tok := l.NextToken()
+		for tok.Type != token.EOF {
+			tok = l.NextToken()
+		}
+
+		errs := l.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("want 1 lexer error, got %d: %v", len(errs), errs)
+		}
+		if want := "1:5: string literal not terminated"; errs[0].Error() != want {
+			t.Errorf("wrong error: want %q, got %q", want, errs[0].Error())
+		}
+	})
+}
+
+func TestCharLiteral(t *testing.T) {
+	t.Run("valid literals", func(t *testing.T) {
+		input := `'a' 'é' '🤗' ' ' '"' '\'' '\\' '\n' '\u{1F917}'`
+
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+			expectedValue   rune
+		}{
+			{expectedType: token.CHAR, expectedLiteral: `'a'`, expectedValue: 'a'},
+			{expectedType: token.CHAR, expectedLiteral: `'é'`, expectedValue: 'é'},
+			{expectedType: token.CHAR, expectedLiteral: `'🤗'`, expectedValue: '🤗'},
+			{expectedType: token.CHAR, expectedLiteral: `' '`, expectedValue: ' '},
+			{expectedType: token.CHAR, expectedLiteral: `'"'`, expectedValue: '"'},
+			{expectedType: token.CHAR, expectedLiteral: `'\''`, expectedValue: '\''},
+			{expectedType: token.CHAR, expectedLiteral: `'\\'`, expectedValue: '\\'},
+			{expectedType: token.CHAR, expectedLiteral: `'\n'`, expectedValue: '\n'},
+			{expectedType: token.CHAR, expectedLiteral: `'\u{1F917}'`, expectedValue: '🤗'},
+		}
+
+		l := lexer.New(input)
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+
+			value, err := lexer.UnquoteChar(tok.Literal)
+			if err != nil {
+				t.Errorf("test #%d unexpected unquote error: %v", i, err)
+			}
+			if value != tC.expectedValue {
+				t.Errorf("test #%d wrong value: want %q, got %q", i, tC.expectedValue, value)
+			}
+		}
+
+		if errs := l.Errors(); len(errs) != 0 {
+			t.Errorf("unexpected lexer errors: %v", errs)
+		}
+	})
+
+	t.Run("malformed literals", func(t *testing.T) {
+		testCases := []struct {
+			input           string
+			expectedLiteral string
+			expectedError   string
+		}{
+			{input: `''`, expectedLiteral: `''`, expectedError: "1:1: empty character literal"},
+			{input: `x == 'ab'`, expectedLiteral: `'ab'`, expectedError: "1:6: more than one character in character literal"},
+			{input: `'\n\t'`, expectedLiteral: `'\n\t'`, expectedError: "1:1: more than one character in character literal"},
+			{input: "'e\u0301'", expectedLiteral: "'e\u0301'", expectedError: "1:1: more than one character in character literal"},
+			{input: `'a`, expectedLiteral: `'a`, expectedError: "1:1: character literal not terminated"},
+			{input: "'\nx'", expectedLiteral: "'", expectedError: "1:1: character literal not terminated"},
+			{input: `'\q'`, expectedLiteral: `'\q'`, expectedError: `1:2: unknown escape sequence \q`},
+			{input: `'\"'`, expectedLiteral: `'\"'`, expectedError: `1:2: unknown escape sequence \"`},
+			{input: `'\u{110000}'`, expectedLiteral: `'\u{110000}'`, expectedError: `1:2: invalid escape sequence \u{110000}: escape is not a valid Unicode code point`},
+		}
+
+		for i, tC := range testCases {
+			l := lexer.New(tC.input)
+
+			tok := l.NextToken()
+			for tok.Type != token.ILLEGAL && tok.Type != token.EOF {
+				tok = l.NextToken()
+			}
+
+			if tok.Type != token.ILLEGAL {
+				t.Fatalf("test #%d wrong token type: want %q, got %q", i, token.ILLEGAL, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+
+			errs := l.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("test #%d want 1 lexer error, got %d: %v", i, len(errs), errs)
+			}
+			if errs[0].Error() != tC.expectedError {
+				t.Errorf("test #%d wrong error: want %q, got %q", i, tC.expectedError, errs[0].Error())
+			}
+		}
+	})
+}
+
+func TestNumberLiteral(t *testing.T) {
+	t.Run("integer and float literals", func(t *testing.T) {
+		input := "5 3.14 .5 1e-9 2E+10 6.02e23 0.0 8., 0xFF 0X1f 0o17 0O7 017 0b1010 0B1 1_000_000 0x_FF 0b_1 1_0.2_5 089.5 0"
+
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+		}{
+			{expectedType: token.INT, expectedLiteral: "5"},
+			{expectedType: token.FLOAT, expectedLiteral: "3.14"},
+			{expectedType: token.FLOAT, expectedLiteral: ".5"},
+			{expectedType: token.FLOAT, expectedLiteral: "1e-9"},
+			{expectedType: token.FLOAT, expectedLiteral: "2E+10"},
+			{expectedType: token.FLOAT, expectedLiteral: "6.02e23"},
+			{expectedType: token.FLOAT, expectedLiteral: "0.0"},
+			{expectedType: token.INT, expectedLiteral: "8"},
+			{expectedType: token.PERIOD, expectedLiteral: "."},
+			{expectedType: token.COMMA, expectedLiteral: ","},
+			{expectedType: token.INT, expectedLiteral: "0xFF"},
+			{expectedType: token.INT, expectedLiteral: "0X1f"},
+			{expectedType: token.INT, expectedLiteral: "0o17"},
+			{expectedType: token.INT, expectedLiteral: "0O7"},
+			{expectedType: token.INT, expectedLiteral: "017"},
+			{expectedType: token.INT, expectedLiteral: "0b1010"},
+			{expectedType: token.INT, expectedLiteral: "0B1"},
+			{expectedType: token.INT, expectedLiteral: "1_000_000"},
+			{expectedType: token.INT, expectedLiteral: "0x_FF"},
+			{expectedType: token.INT, expectedLiteral: "0b_1"},
+			{expectedType: token.FLOAT, expectedLiteral: "1_0.2_5"},
+			{expectedType: token.FLOAT, expectedLiteral: "089.5"},
+			{expectedType: token.INT, expectedLiteral: "0"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+		}
+
+		l := lexer.New(input)
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+		}
+
+		if errs := l.Errors(); len(errs) != 0 {
+			t.Errorf("unexpected lexer errors: %v", errs)
+		}
+	})
+
+	t.Run("malformed literals", func(t *testing.T) {
+		testCases := []struct {
+			input           string
+			expectedLiteral string
+			expectedError   string
+		}{
+			{input: "1e", expectedLiteral: "1e", expectedError: "1:1: exponent has no digits"},
+			{input: "x = 2.5e+;", expectedLiteral: "2.5e+", expectedError: "1:5: exponent has no digits"},
+			{input: "0x", expectedLiteral: "0x", expectedError: "1:1: hexadecimal literal has no digits"},
+			{input: "0b", expectedLiteral: "0b", expectedError: "1:1: binary literal has no digits"},
+			{input: "0b2", expectedLiteral: "0b2", expectedError: "1:3: invalid digit '2' in binary literal"},
+			{input: "0o19", expectedLiteral: "0o19", expectedError: "1:4: invalid digit '9' in octal literal"},
+			{input: "089", expectedLiteral: "089", expectedError: "1:2: invalid digit '8' in octal literal"},
+			{input: "1__0", expectedLiteral: "1__0", expectedError: "1:3: '_' must separate successive digits"},
+			{input: "10_", expectedLiteral: "10_", expectedError: "1:3: '_' must separate successive digits"},
+			{input: "0_x1", expectedLiteral: "0_", expectedError: "1:2: '_' must separate successive digits"},
+			{input: "1_.5", expectedLiteral: "1_.5", expectedError: "1:2: '_' must separate successive digits"},
+		}
+
+		for i, tC := range testCases {
+			l := lexer.New(tC.input)
+
+			tok := l.NextToken()
+			for tok.Type != token.ILLEGAL && tok.Type != token.EOF {
+				tok = l.NextToken()
+			}
+
+			if tok.Type != token.ILLEGAL {
+				t.Fatalf("test #%d wrong token type: want %q, got %q", i, token.ILLEGAL, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+
+			errs := l.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("test #%d want 1 lexer error, got %d: %v", i, len(errs), errs)
+			}
+			if errs[0].Error() != tC.expectedError {
+				t.Errorf("test #%d wrong error: want %q, got %q", i, tC.expectedError, errs[0].Error())
+			}
+		}
+	})
+}
+
+func TestComments(t *testing.T) {
+	input := commentsInput
+
+	t.Run("skipped by default", func(t *testing.T) {
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+		}{
+			{expectedType: token.LET, expectedLiteral: "let"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.ASSIGN, expectedLiteral: "="},
+			{expectedType: token.INT, expectedLiteral: "5"},
+			{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.SLASH, expectedLiteral: "/"},
+			{expectedType: token.IDENT, expectedLiteral: "y"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+		}
+
+		l := lexer.New(input)
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+		}
+	})
+
+	t.Run("kept as tokens", func(t *testing.T) {
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+		}{
+			{expectedType: token.COMMENT, expectedLiteral: "// leading comment"},
+			{expectedType: token.LET, expectedLiteral: "let"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.ASSIGN, expectedLiteral: "="},
+			{expectedType: token.INT, expectedLiteral: "5"},
+			{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+			{expectedType: token.COMMENT, expectedLiteral: "// trailing comment"},
+			{expectedType: token.COMMENT, expectedLiteral: "/* block /* nested */ still comment */"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.SLASH, expectedLiteral: "/"},
+			{expectedType: token.IDENT, expectedLiteral: "y"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.COMMENT, expectedLiteral: "/**/"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+		}
+
+		l := lexer.New(input, lexer.WithComments())
+
+		for i, tC := range testCases {
+			tok := l.NextToken()
+
+			if tok.Type != tC.expectedType {
+				t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+			}
+
+			if tok.Literal != tC.expectedLiteral {
+				t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+			}
+		}
+	})
+
+	t.Run("doc comments", func(t *testing.T) {
+		input := "/// doc\n//// not doc\nx /// trailing\n///\n"
+
+		testCases := []struct {
+			expectedType    token.TokenType
+			expectedLiteral string
+		}{
+			{expectedType: token.DOC_COMMENT, expectedLiteral: "/// doc"},
+			{expectedType: token.IDENT, expectedLiteral: "x"},
+			{expectedType: token.DOC_COMMENT, expectedLiteral: "/// trailing"},
+			{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+			{expectedType: token.DOC_COMMENT, expectedLiteral: "///"},
+			{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+		}
+
+		for _, opts := range [][]lexer.Option{nil, {lexer.WithTrivia()}} {
+			l := lexer.New(input, opts...)
+
+			for i, tC := range testCases {
+				tok := l.NextToken()
+
+				if tok.Type != tC.expectedType {
+					t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+				}
+
+				if tok.Literal != tC.expectedLiteral {
+					t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+				}
+			}
+		}
+	})
+
+	t.Run("unterminated block comment", func(t *testing.T) {
+		l := lexer.New("x\n  /* outer /* inner */ y")
+
+		for _, want := range []token.TokenType{token.IDENT, token.SEMICOLON} {
+			if tok := l.NextToken(); tok.Type != want {
+				t.Fatalf("wrong token type: want %q, got %q", want, tok.Type)
+			}
+		}
+
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("wrong token type: want %q, got %q", token.ILLEGAL, tok.Type)
+		}
+		if want := "/* outer /* inner */ y"; tok.Literal != want {
+			t.Errorf("wrong literal: want %q, got %q", want, tok.Literal)
+		}
+
+		errs := l.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("want 1 lexer error, got %d: %v", len(errs), errs)
+		}
+		if want := "2:3: block comment not terminated"; errs[0].Error() != want {
+			t.Errorf("wrong error: want %q, got %q", want, errs[0].Error())
+		}
+	})
+}
+
+func TestAutomaticSemicolons(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		input         string
+		expectedTypes []token.TokenType
+	}{
+		{
+			desc:          "after identifiers and literals",
+			input:         "x\n5\n2.5\n\"s\"\ntrue\nfalse\n",
+			expectedTypes: []token.TokenType{token.IDENT, token.SEMICOLON, token.INT, token.SEMICOLON, token.FLOAT, token.SEMICOLON, token.STRING, token.SEMICOLON, token.TRUE, token.SEMICOLON, token.FALSE, token.SEMICOLON, token.EOF},
+		},
+		{
+			desc:          "after closing delimiters and return",
+			input:         "f()\n{}\nreturn\n",
+			expectedTypes: []token.TokenType{token.IDENT, token.LPAREN, token.RPAREN, token.SEMICOLON, token.LBRACE, token.RBRACE, token.SEMICOLON, token.RETURN, token.SEMICOLON, token.EOF},
+		},
+		{
+			desc:          "not after operators and keywords",
+			input:         "let\nx =\n1 +\n2\nfn(\n)",
+			expectedTypes: []token.TokenType{token.LET, token.IDENT, token.ASSIGN, token.INT, token.PLUS, token.INT, token.SEMICOLON, token.FUNCTION, token.LPAREN, token.RPAREN, token.SEMICOLON, token.EOF},
+		},
+		{
+			desc:          "once for blank lines",
+			input:         "x\r\n\r\n\n  y",
+			expectedTypes: []token.TokenType{token.IDENT, token.SEMICOLON, token.IDENT, token.SEMICOLON, token.EOF},
+		},
+		{
+			desc:          "at line breaks in comments",
+			input:         "x // comment\ny /* multi\nline */ z /* inline */\n",
+			expectedTypes: []token.TokenType{token.IDENT, token.SEMICOLON, token.IDENT, token.SEMICOLON, token.IDENT, token.SEMICOLON, token.EOF},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			l := lexer.New(tC.input)
+
+			for i, want := range tC.expectedTypes {
+				tok := l.NextToken()
+
+				if tok.Type != want {
+					t.Errorf("test #%d wrong token type: want %q, got %q", i, want, tok.Type)
+				}
+				if tok.Type == token.SEMICOLON && tok.Literal != "\n" {
+					t.Errorf("test #%d wrong literal: want %q, got %q", i, "\n", tok.Literal)
+				}
+			}
+		})
+	}
+
+	t.Run("positioned at the line break", func(t *testing.T) {
+		l := lexer.New("x  \ny")
+		l.NextToken()
+
+		tok := l.NextToken()
+		if want := (token.Position{Offset: 3, Line: 1, Column: 4}); tok.Pos != want {
+			t.Errorf("wrong position: want %+v, got %+v", want, tok.Pos)
+		}
+	})
+}
+
+func TestPrologue(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		input string
+	}{
+		{desc: "shebang", input: "#!/usr/bin/env go-inter\nx"},
+		{desc: "byte order mark", input: "\uFEFF\nx"},
+		{desc: "byte order mark and shebang", input: "\uFEFF#!/usr/bin/env go-inter -v\r\nx"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			l := lexer.New(tC.input)
+
+			tok := l.NextToken()
+			if tok.Type != token.IDENT {
+				t.Errorf("wrong token type: want %q, got %q", token.IDENT, tok.Type)
+			}
+			if want := (token.Position{Offset: len(tC.input) - 1, Line: 2, Column: 1}); tok.Pos != want {
+				t.Errorf("wrong position: want %+v, got %+v", want, tok.Pos)
+			}
+
+			if errs := l.Errors(); len(errs) != 0 {
+				t.Errorf("unexpected lexer errors: %v", errs)
+			}
+		})
+	}
+
+	t.Run("only at the start", func(t *testing.T) {
+		l := lexer.New("x\n#!y")
+
+		for _, want := range []token.TokenType{token.IDENT, token.SEMICOLON, token.ILLEGAL, token.BANG, token.IDENT} {
+			if tok := l.NextToken(); tok.Type != want {
+				t.Errorf("wrong token type: want %q, got %q", want, tok.Type)
+			}
+		}
+	})
+}
+
+func TestLineBreaks(t *testing.T) {
+	input := "a\r\nb\rc\n\r\nd /* \r\n */ e // f\r\ng"
+
+	testCases := []struct {
+		expectedType token.TokenType
+		expectedPos  token.Position
+	}{
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 0, Line: 1, Column: 1}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Offset: 1, Line: 1, Column: 2}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 3, Line: 2, Column: 1}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Offset: 4, Line: 2, Column: 2}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 5, Line: 3, Column: 1}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Offset: 6, Line: 3, Column: 2}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 9, Line: 5, Column: 1}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Offset: 19, Line: 6, Column: 4}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 20, Line: 6, Column: 5}},
+		{expectedType: token.SEMICOLON, expectedPos: token.Position{Offset: 26, Line: 6, Column: 11}},
+		{expectedType: token.IDENT, expectedPos: token.Position{Offset: 28, Line: 7, Column: 1}},
+	}
+
+	l := lexer.New(input)
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Pos != tC.expectedPos {
+			t.Errorf("test #%d wrong position: want %+v, got %+v", i, tC.expectedPos, tok.Pos)
+		}
+	}
+
+	t.Run("end string literals", func(t *testing.T) {
+		l := lexer.New("\"abc\r\n")
+
+		if tok := l.NextToken(); tok.Type != token.ILLEGAL || tok.Literal != `"abc` {
+			t.Errorf("wrong token: want ILLEGAL %q, got %s %q", `"abc`, tok.Type, tok.Literal)
+		}
+	})
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	testCases := []struct {
+		input           string
+		expectedLiteral string
+		expectedError   string
+	}{
+		{input: "x \xff", expectedLiteral: "\xff", expectedError: "1:3: invalid UTF-8 encoding"},
+		{input: "\xe2\x82", expectedLiteral: "\xe2", expectedError: "1:1: invalid UTF-8 encoding"},
+		{input: "\"a\xffb\"", expectedLiteral: "\"a\xffb\"", expectedError: "1:3: invalid UTF-8 encoding"},
+		{input: "'\xff'", expectedLiteral: "'\xff'", expectedError: "1:2: invalid UTF-8 encoding"},
+		{input: "`\n\xff`", expectedLiteral: "`\n\xff`", expectedError: "2:1: invalid UTF-8 encoding"},
+	}
+
+	for i, tC := range testCases {
+		l := lexer.New(tC.input)
+
+		tok := l.NextToken()
+		for tok.Type != token.ILLEGAL && tok.Type != token.EOF {
+			tok = l.NextToken()
+		}
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("test #%d wrong token type: want %q, got %q", i, token.ILLEGAL, tok.Type)
+		}
+
+		if tok.Literal != tC.expectedLiteral {
+			t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+		}
+
+		errs := l.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("test #%d want 1 lexer error, got %d: %v", i, len(errs), errs)
+		}
+		if errs[0].Error() != tC.expectedError {
+			t.Errorf("test #%d wrong error: want %q, got %q", i, tC.expectedError, errs[0].Error())
+		}
+	}
+
+	t.Run("encoded replacement character", func(t *testing.T) {
+		l := lexer.New("\uFFFD")
+
+		l.NextToken()
+		if errs := l.Errors(); len(errs) != 1 || errs[0].Reason != "unexpected '\uFFFD'" {
+			t.Errorf("wrong errors: %v", errs)
+		}
+	})
+}
+
+func TestIllegalCharacters(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedReason string
+		expectedHint   string
+	}{
+		{input: "$x", expectedReason: "unexpected '$'", expectedHint: "did you mean to start an identifier?"},
+		{input: "a & b", expectedReason: "'&' is not an operator", expectedHint: "use '&&'"},
+		{input: "a | b", expectedReason: "'|' is not an operator", expectedHint: "use '||' or '|>'"},
+		{input: "x # note", expectedReason: "unexpected '#'", expectedHint: "comments start with //"},
+		{input: "“hi”", expectedReason: "unexpected '“'", expectedHint: `strings are quoted with '"'`},
+		{input: "x\uFEFF", expectedReason: "unexpected byte order mark", expectedHint: "a byte order mark is only allowed at the start of the input"},
+		{input: "@", expectedReason: "unexpected '@'"},
+	}
+
+	for i, tC := range testCases {
+		l := lexer.New(tC.input)
+
+		tok := l.NextToken()
+		for tok.Type != token.ILLEGAL && tok.Type != token.EOF {
+			tok = l.NextToken()
+		}
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("test #%d wrong token type: want %q, got %q", i, token.ILLEGAL, tok.Type)
+		}
+
+		errs := l.Errors()
+		if len(errs) == 0 {
+			t.Fatalf("test #%d want lexer errors", i)
+		}
+		if errs[0].Pos != tok.Pos {
+			t.Errorf("test #%d wrong error position: want %s, got %s", i, tok.Pos, errs[0].Pos)
+		}
+		if errs[0].Reason != tC.expectedReason {
+			t.Errorf("test #%d wrong reason: want %q, got %q", i, tC.expectedReason, errs[0].Reason)
+		}
+		if errs[0].Hint != tC.expectedHint {
+			t.Errorf("test #%d wrong hint: want %q, got %q", i, tC.expectedHint, errs[0].Hint)
+		}
+	}
+
+	t.Run("error text", func(t *testing.T) {
+		l := lexer.New("x = $y")
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		if want := "1:5: unexpected '$' — did you mean to start an identifier?"; l.Errors()[0].Error() != want {
+			t.Errorf("wrong error: want %q, got %q", want, l.Errors()[0].Error())
+		}
+	})
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	const (
+		cafeNFC = "caf\u00e9"  // é as a single code point
+		cafeNFD = "cafe\u0301" // e followed by a combining acute accent
+	)
+
+	input := "let " + cafeNFC + " = 1; let π = 3; 変数 + x١ - Straße; " + cafeNFD
+
+	testCases := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{expectedType: token.LET, expectedLiteral: "let"},
+		{expectedType: token.IDENT, expectedLiteral: cafeNFC},
+		{expectedType: token.ASSIGN, expectedLiteral: "="},
+		{expectedType: token.INT, expectedLiteral: "1"},
+		{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+		{expectedType: token.LET, expectedLiteral: "let"},
+		{expectedType: token.IDENT, expectedLiteral: "π"},
+		{expectedType: token.ASSIGN, expectedLiteral: "="},
+		{expectedType: token.INT, expectedLiteral: "3"},
+		{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+		{expectedType: token.IDENT, expectedLiteral: "変数"},
+		{expectedType: token.PLUS, expectedLiteral: "+"},
+		{expectedType: token.IDENT, expectedLiteral: "x١"},
+		{expectedType: token.MINUS, expectedLiteral: "-"},
+		{expectedType: token.IDENT, expectedLiteral: "Straße"},
+		{expectedType: token.SEMICOLON, expectedLiteral: ";"},
+		{expectedType: token.IDENT, expectedLiteral: cafeNFC},
+		{expectedType: token.SEMICOLON, expectedLiteral: "\n"},
+		{expectedType: token.EOF, expectedLiteral: string(rune(0))},
+	}
+
+	l := lexer.New(input)
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tC.expectedLiteral {
+			t.Errorf("test #%d wrong literal: want %q, got %q", i, tC.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestDialects(t *testing.T) {
+	scripting := scriptingDialect()
+	spanish := spanishDialect()
+
+	input := "while const fn funcion let x 🤗 y"
+
+	testCases := []struct {
+		dialect        *token.Dialect
+		expectedTypes  []token.TokenType
+		expectedErrors []string
+	}{
+		{
+			dialect: token.Default,
+			expectedTypes: []token.TokenType{
+				token.IDENT, token.IDENT, token.FUNCTION, token.IDENT, token.LET, token.IDENT, token.HUG, token.IDENT, token.SEMICOLON, token.EOF,
+			},
+		},
+		{
+			dialect: scripting,
+			expectedTypes: []token.TokenType{
+				token.WHILE, token.CONST, token.FUNCTION, token.IDENT, token.LET, token.IDENT, token.HUG, token.IDENT, token.SEMICOLON, token.EOF,
+			},
+		},
+		{
+			dialect: spanish,
+			expectedTypes: []token.TokenType{
+				token.IDENT, token.IDENT, token.IDENT, token.FUNCTION, token.LET, token.IDENT, token.ILLEGAL, token.IDENT, token.SEMICOLON, token.EOF,
+			},
+			expectedErrors: []string{"1:30: operator 🤗 is not supported in this dialect"},
+		},
+	}
+
+	for i, tC := range testCases {
+		l := lexer.New(input, lexer.WithDialect(tC.dialect))
+
+		for j, want := range tC.expectedTypes {
+			if tok := l.NextToken(); tok.Type != want {
+				t.Errorf("dialect #%d token #%d wrong token type: want %q, got %q", i, j, want, tok.Type)
+			}
+		}
+
+		errs := l.Errors()
+		if len(errs) != len(tC.expectedErrors) {
+			t.Fatalf("dialect #%d want %d lexer errors, got %d: %v", i, len(tC.expectedErrors), len(errs), errs)
+		}
+		for j, want := range tC.expectedErrors {
+			if errs[j].Error() != want {
+				t.Errorf("dialect #%d wrong error: want %q, got %q", i, want, errs[j].Error())
+			}
+		}
+	}
+}
+
+// scriptingDialect returns the default dialect extended with while and const.
+func scriptingDialect() *token.Dialect {
+	keywords := token.Default.Keywords()
+	keywords["while"] = token.WHILE
+	keywords["const"] = token.CONST
+	return token.NewDialect(keywords, token.Default.Operators())
+}
+
+// spanishDialect returns the default dialect with fn spelled funcion and
+// without the 🤗 operator.
+func spanishDialect() *token.Dialect {
+	keywords := token.Default.Keywords()
+	delete(keywords, "fn")
+	keywords["funcion"] = token.FUNCTION
+
+	var ops []token.TokenType
+	for _, op := range token.Default.Operators() {
+		if op != token.HUG {
+			ops = append(ops, op)
+		}
+	}
+	return token.NewDialect(keywords, ops)
 }
@@ -0,0 +1,467 @@
+package lexer
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antklim/go-inter/token"
+)
+
+// readNumber reads an integer or floating-point literal and returns its token
+// type and source text. Integer literals follow the Go syntax: decimal, 0x
+// hexadecimal, 0o or legacy 0 octal and 0b binary, with optional '_' digit
+// separators. A floating-point literal has a decimal mantissa with a
+// fractional part, an exponent or both, e.g. 3.14, .5 or 1e-9. A malformed
+// literal is returned as token.ILLEGAL and the reason is recorded in l.errors.
+func (l *Lexer) readNumber() (token.TokenType, string) {
+	start := l.position()
+	errorCount := len(l.errors)
+	tt := token.INT
+	base, prefix := 10, rune(0)
+	var invalid token.Position // position of the first digit not valid in base
+
+	if l.ch == '0' {
+		switch lower(rune(l.peekChar())) {
+		case 'x':
+			base, prefix = 16, 'x'
+		case 'o':
+			base, prefix = 8, 'o'
+		case 'b':
+			base, prefix = 2, 'b'
+		default:
+			base, prefix = 8, '0'
+		}
+		if prefix != '0' {
+			l.readChar()
+			l.readChar()
+		}
+	}
+
+	digsep := l.readDigits(base, &invalid)
+	if prefix != 0 && prefix != '0' && digsep&1 == 0 {
+		l.errorf(start, "%s literal has no digits", baseName(base))
+	}
+
+	if prefix == 0 || prefix == '0' {
+		if l.ch == '.' && isDigit(rune(l.peekChar())) {
+			tt = token.FLOAT
+			l.readChar()
+			digsep |= l.readDigits(10, &invalid)
+		}
+
+		if lower(l.ch) == 'e' {
+			tt = token.FLOAT
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				l.readChar()
+			}
+			ds := l.readDigits(10, &invalid)
+			digsep |= ds
+			if ds&1 == 0 {
+				l.errorf(start, "exponent has no digits")
+			}
+		}
+	}
+
+	lit := l.slice(start.Offset, l.chPosition)
+
+	if tt == token.INT && invalid.IsValid() {
+		l.errorf(invalid, "invalid digit %q in %s literal", lit[invalid.Offset-start.Offset], baseName(base))
+	}
+
+	if digsep&2 != 0 {
+		if i := invalidSep(lit); i >= 0 {
+			pos := start
+			pos.Offset += i
+			pos.Column += i
+			l.errorf(pos, "'_' must separate successive digits")
+		}
+	}
+
+	if len(l.errors) > errorCount {
+		tt = token.ILLEGAL
+	}
+
+	return tt, lit
+}
+
+// readDigits reads a run of digits and '_' separators. Decimal digits that
+// are not valid in base are consumed as well and the position of the first
+// one is stored in invalid. The result has bit 0 set if any digit was read
+// and bit 1 set if any separator was read.
+func (l *Lexer) readDigits(base int, invalid *token.Position) int {
+	digsep := 0
+	maxDigit := rune('0' + base)
+
+	for isDigit(l.ch) || l.ch == '_' || base == 16 && isHexDigit(l.ch) {
+		ds := 1
+		if l.ch == '_' {
+			ds = 2
+		} else if base < 10 && l.ch >= maxDigit && !invalid.IsValid() {
+			*invalid = l.position()
+		}
+		digsep |= ds
+		l.readChar()
+	}
+
+	return digsep
+}
+
+// invalidSep returns the index of the first '_' in the number literal x that
+// does not separate two digits (or a base prefix and a digit), or -1 if all
+// separators are valid.
+func invalidSep(x string) int {
+	x1 := ' ' // prefix letter, only 'x' changes which characters are digits
+	d := '.'  // previous character class: '_', '0' (a digit) or '.' (other)
+	i := 0
+
+	// a base prefix counts as a digit
+	if len(x) >= 2 && x[0] == '0' {
+		x1 = lower(rune(x[1]))
+		if x1 == 'x' || x1 == 'o' || x1 == 'b' {
+			d = '0'
+			i = 2
+		}
+	}
+
+	for ; i < len(x); i++ {
+		p := d
+		d = rune(x[i])
+		switch {
+		case d == '_':
+			if p != '0' {
+				return i
+			}
+		case isDigit(d) || x1 == 'x' && isHexDigit(d):
+			d = '0'
+		default:
+			if p == '_' {
+				return i - 1
+			}
+			d = '.'
+		}
+	}
+	if d == '_' {
+		return len(x) - 1
+	}
+
+	return -1
+}
+
+func baseName(base int) string {
+	switch base {
+	case 2:
+		return "binary"
+	case 8:
+		return "octal"
+	case 16:
+		return "hexadecimal"
+	default:
+		return "decimal"
+	}
+}
+
+func lower(ch rune) rune {
+	return ('a' - 'A') | ch
+}
+
+// interpolation is an entry of the lexer mode stack: an open ${...} in a
+// string literal. While it is open the lexer reads ordinary tokens, until the
+// '}' that matches the opening "${".
+type interpolation struct {
+	start  token.Position // start of the string literal
+	braces int            // '{' opened inside the interpolation and not yet closed
+}
+
+// readStringLiteral reads a double-quoted string literal starting at the
+// current '"', or the rest of one starting at the '}' that closes an
+// interpolation, and returns its token type and source text.
+//
+// A literal without interpolations is a token.STRING. Otherwise its parts up
+// to and including each "${" are a token.STRING_START and then
+// token.STRING_MID tokens, and the last part, from the final '}', is a
+// token.STRING_END. Malformed literals are token.ILLEGAL, the reason is
+// recorded in l.errors.
+func (l *Lexer) readStringLiteral() (token.TokenType, string) {
+	start := l.position()
+	tt, last := token.STRING, token.STRING_START
+	if l.ch == '}' {
+		n := len(l.interpolations) - 1
+		start = l.interpolations[n].start
+		l.interpolations = l.interpolations[:n]
+		tt, last = token.STRING_END, token.STRING_MID
+	}
+	from := l.chPosition
+	valid := true
+
+	l.readChar() // opening quote or closing brace
+	for l.ch != '"' {
+		switch l.ch {
+		case 0, '\n', '\r':
+			l.errorf(start, "string literal not terminated")
+			return token.ILLEGAL, l.slice(from, l.chPosition)
+		case '\\':
+			if !l.readEscape('"') {
+				valid = false
+			}
+		case '$':
+			l.readChar()
+			if l.ch != '{' {
+				continue
+			}
+			l.readChar()
+			l.interpolations = append(l.interpolations, interpolation{start: start})
+			if !valid {
+				return token.ILLEGAL, l.slice(from, l.chPosition)
+			}
+			return last, l.slice(from, l.chPosition)
+		default:
+			if l.invalidUTF8() {
+				l.errorf(l.position(), "invalid UTF-8 encoding")
+				valid = false
+			}
+			l.readChar()
+		}
+	}
+	l.readChar() // closing quote
+
+	if !valid {
+		return token.ILLEGAL, l.slice(from, l.chPosition)
+	}
+	return tt, l.slice(from, l.chPosition)
+}
+
+// readRawString reads a backtick-quoted raw string literal, which may span
+// lines and has no escape sequences, and returns its source text. The second
+// result is false if the literal is malformed.
+func (l *Lexer) readRawString() (string, bool) {
+	start := l.position()
+	valid := true
+
+	l.readChar() // opening backtick
+	for l.ch != '`' {
+		if l.ch == 0 {
+			l.errorf(start, "raw string literal not terminated")
+			return l.slice(start.Offset, l.chPosition), false
+		}
+		if l.invalidUTF8() {
+			l.errorf(l.position(), "invalid UTF-8 encoding")
+			valid = false
+		}
+		l.readChar()
+	}
+	l.readChar() // closing backtick
+
+	return l.slice(start.Offset, l.chPosition), valid
+}
+
+// readCharLiteral reads a character literal starting at the current single
+// quote. It reports false if the literal is malformed.
+func (l *Lexer) readCharLiteral() (string, bool) {
+	start := l.position()
+	valid := true
+	n := 0 // characters between the quotes, an escape sequence counts as one
+
+	l.readChar() // opening quote
+	for ; l.ch != '\''; n++ {
+		switch l.ch {
+		case 0, '\n', '\r':
+			l.errorf(start, "character literal not terminated")
+			return l.slice(start.Offset, l.chPosition), false
+		case '\\':
+			if !l.readEscape('\'') {
+				valid = false
+			}
+		default:
+			if l.invalidUTF8() {
+				l.errorf(l.position(), "invalid UTF-8 encoding")
+				valid = false
+			}
+			l.readChar()
+		}
+	}
+	l.readChar() // closing quote
+
+	switch {
+	case !valid:
+	case n == 0:
+		l.errorf(start, "empty character literal")
+		valid = false
+	case n > 1:
+		l.errorf(start, "more than one character in character literal")
+		valid = false
+	}
+
+	return l.slice(start.Offset, l.chPosition), valid
+}
+
+// readEscape reads an escape sequence starting at the current backslash.
+// quote is the delimiter of the enclosing literal.
+func (l *Lexer) readEscape(quote rune) bool {
+	pos := l.position()
+	l.readChar() // backslash
+
+	switch l.ch {
+	case 'n', 't', 'r', '\\', '$', quote:
+		l.readChar()
+		return true
+	case 'u':
+		l.readChar()
+		if l.ch != '{' {
+			l.errorf(pos, "invalid escape sequence: missing '{' after \\u")
+			return false
+		}
+		l.readChar()
+
+		start := l.chPosition
+		for isHexDigit(l.ch) {
+			l.readChar()
+		}
+		digits := l.slice(start, l.chPosition)
+
+		if l.ch != '}' {
+			l.errorf(pos, "invalid escape sequence: missing '}' in \\u{%s", digits)
+			return false
+		}
+		l.readChar()
+
+		if _, err := decodeCodePoint(digits); err != nil {
+			l.errorf(pos, "invalid escape sequence \\u{%s}: %s", digits, err)
+			return false
+		}
+		return true
+	case 0, '\n', '\r':
+		l.errorf(pos, "invalid escape sequence: missing escaped character")
+		return false
+	default:
+		l.errorf(pos, "unknown escape sequence \\%c", l.ch)
+		l.readChar()
+		return false
+	}
+}
+
+// Unquote interprets lit as a string literal and returns the string value
+// it represents. lit is the Literal of a token.STRING token, or of a part of
+// an interpolated string: the text of a token.STRING_START, token.STRING_MID
+// or token.STRING_END token between the quote, "${" and '}' that delimit it.
+// Carriage returns are removed from raw strings.
+func Unquote(lit string) (string, error) {
+	if len(lit) >= 2 && lit[0] == '`' && lit[len(lit)-1] == '`' {
+		return strings.ReplaceAll(lit[1:len(lit)-1], "\r", ""), nil
+	}
+
+	s, ok := strings.CutPrefix(lit, `"`)
+	if !ok {
+		s, ok = strings.CutPrefix(lit, "}")
+	}
+	if ok && len(s) > 0 {
+		if t, found := strings.CutSuffix(s, `"`); found {
+			return unquote(t, '"')
+		}
+		if t, found := strings.CutSuffix(s, "${"); found {
+			return unquote(t, '"')
+		}
+	}
+
+	return "", errors.New("invalid string literal")
+}
+
+// UnquoteChar interprets lit as a character literal, as returned in the
+// Literal of a token.CHAR token, and returns the rune it represents.
+func UnquoteChar(lit string) (rune, error) {
+	if len(lit) < 2 || lit[0] != '\'' || lit[len(lit)-1] != '\'' {
+		return 0, errors.New("invalid character literal")
+	}
+
+	s, err := unquote(lit[1:len(lit)-1], '\'')
+	if err != nil {
+		return 0, err
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	switch {
+	case size == 0:
+		return 0, errors.New("empty character literal")
+	case size != len(s):
+		return 0, errors.New("more than one character in character literal")
+	}
+
+	return r, nil
+}
+
+// unquote interprets the escape sequences in s, the text of a literal between
+// its quotes. quote is the delimiter of the literal.
+func unquote(s string, quote byte) (string, error) {
+	if !strings.ContainsRune(s, '\\') {
+		return s, nil
+	}
+
+	var out strings.Builder
+	for len(s) > 0 {
+		if s[0] != '\\' {
+			out.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+		if len(s) < 2 {
+			return "", errors.New("invalid escape sequence: missing escaped character")
+		}
+
+		switch c := s[1]; c {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '\\', '$', quote:
+			out.WriteByte(c)
+		case 'u':
+			end := strings.IndexByte(s, '}')
+			if len(s) < 3 || s[2] != '{' || end < 0 {
+				return "", errors.New("invalid \\u escape sequence")
+			}
+			r, err := decodeCodePoint(s[3:end])
+			if err != nil {
+				return "", err
+			}
+			out.WriteRune(r)
+			s = s[end+1:]
+			continue
+		default:
+			r, _ := utf8.DecodeRuneInString(s[1:])
+			return "", errors.New("unknown escape sequence \\" + string(r))
+		}
+		s = s[2:]
+	}
+
+	return out.String(), nil
+}
+
+// decodeCodePoint converts the hexadecimal digits of a \u{...} escape to the
+// rune they denote.
+func decodeCodePoint(digits string) (rune, error) {
+	if len(digits) == 0 || len(digits) > 6 {
+		return 0, errors.New("expected 1 to 6 hexadecimal digits")
+	}
+
+	v, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, err
+	}
+
+	r := rune(v)
+	if !utf8.ValidRune(r) {
+		return 0, errors.New("escape is not a valid Unicode code point")
+	}
+
+	return r, nil
+}
+
+func isHexDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9' ||
+		'a' <= ch && ch <= 'f' ||
+		'A' <= ch && ch <= 'F'
+}
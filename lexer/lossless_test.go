@@ -0,0 +1,106 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/token"
+)
+
+func TestLosslessRoundTrip(t *testing.T) {
+	inputs := []string{
+		singleCharactersInput,
+		singleIdentifierInput,
+		assignmentsInput,
+		ifStatementsInput,
+		equalityInput,
+		commentsInput,
+		"let caf\u0065\u0301 = 1; // not in NFC\n",
+		"  \t\n",
+		"x /* unterminated",
+	}
+	inputs = append(inputs, readerCorpus...)
+
+	for i, input := range append(inputs, crlf(inputs)...) {
+		want, _ := tokenize(lexer.New(input))
+
+		lexers := map[string]*lexer.Lexer{
+			"New":                     lexer.New(input, lexer.WithTrivia()),
+			"New with comments":       lexer.New(input, lexer.WithTrivia(), lexer.WithComments()),
+			"NewReader":               lexer.NewReader(strings.NewReader(input), lexer.WithTrivia(), lexer.WithBufferSize(3)),
+			"NewReader with comments": lexer.NewReader(strings.NewReader(input), lexer.WithTrivia(), lexer.WithComments(), lexer.WithBufferSize(3)),
+		}
+
+		for name, l := range lexers {
+			got, _ := tokenize(l)
+
+			if src := lexer.Source(got); src != input {
+				t.Errorf("input #%d, %s: source not reproduced\n\twant %q\n\t got %q", i, name, input, src)
+			}
+
+			var toks []token.Token
+			for _, tok := range got {
+				if tok.Type != token.COMMENT {
+					toks = append(toks, tok)
+				}
+			}
+			if len(toks) != len(want) {
+				t.Fatalf("input #%d, %s: want %d tokens, got %d", i, name, len(want), len(toks))
+			}
+			for j := range want {
+				if toks[j].Type != want[j].Type || toks[j].Literal != want[j].Literal || toks[j].Pos != want[j].Pos {
+					t.Errorf("input #%d, %s, token #%d:\n\twant %+v\n\t got %+v", i, name, j, want[j], toks[j])
+				}
+			}
+		}
+	}
+}
+
+func TestTrivia(t *testing.T) {
+	input := "// header\r\nlet x = 5; // five\r\n\r\n  /* doc */ x\r\n"
+
+	testCases := []struct {
+		expectedType     token.TokenType
+		expectedLeading  string
+		expectedText     string
+		expectedTrailing string
+	}{
+		{expectedType: token.LET, expectedLeading: "// header\r\n", expectedText: "let", expectedTrailing: " "},
+		{expectedType: token.IDENT, expectedLeading: "", expectedText: "x", expectedTrailing: " "},
+		{expectedType: token.ASSIGN, expectedLeading: "", expectedText: "=", expectedTrailing: " "},
+		{expectedType: token.INT, expectedLeading: "", expectedText: "5", expectedTrailing: ""},
+		{expectedType: token.SEMICOLON, expectedLeading: "", expectedText: ";", expectedTrailing: " // five"},
+		{expectedType: token.IDENT, expectedLeading: "\r\n\r\n  /* doc */ ", expectedText: "x", expectedTrailing: ""},
+		{expectedType: token.SEMICOLON, expectedLeading: "", expectedText: "", expectedTrailing: ""},
+		{expectedType: token.EOF, expectedLeading: "\r\n", expectedText: "", expectedTrailing: ""},
+	}
+
+	l := lexer.New(input, lexer.WithTrivia())
+
+	for i, tC := range testCases {
+		tok := l.NextToken()
+
+		if tok.Type != tC.expectedType {
+			t.Errorf("test #%d wrong token type: want %q, got %q", i, tC.expectedType, tok.Type)
+		}
+		if tok.Leading != tC.expectedLeading {
+			t.Errorf("test #%d wrong leading trivia: want %q, got %q", i, tC.expectedLeading, tok.Leading)
+		}
+		if tok.Text != tC.expectedText {
+			t.Errorf("test #%d wrong text: want %q, got %q", i, tC.expectedText, tok.Text)
+		}
+		if tok.Trailing != tC.expectedTrailing {
+			t.Errorf("test #%d wrong trailing trivia: want %q, got %q", i, tC.expectedTrailing, tok.Trailing)
+		}
+	}
+}
+
+// crlf returns copies of inputs with Windows line endings.
+func crlf(inputs []string) []string {
+	out := make([]string, len(inputs))
+	for i, input := range inputs {
+		out[i] = strings.ReplaceAll(input, "\n", "\r\n")
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package lexer
+
+import "github.com/antklim/go-inter/token"
+
+// Option configures a Lexer.
+type Option func(*Lexer)
+
+// WithFilename sets the file name reported in token positions.
+func WithFilename(name string) Option {
+	return func(l *Lexer) {
+		l.filename = name
+	}
+}
+
+// WithComments makes the Lexer return comments as token.COMMENT tokens. By
+// default comments are skipped like whitespace. Doc comments are always
+// returned, as token.DOC_COMMENT tokens.
+func WithComments() Option {
+	return func(l *Lexer) {
+		l.keepComments = true
+	}
+}
+
+// WithBufferSize sets the size of the read buffer of a Lexer created with
+// NewReader. It has no effect on New.
+func WithBufferSize(size int) Option {
+	return func(l *Lexer) {
+		if size > 0 {
+			l.bufSize = size
+		}
+	}
+}
+
+// WithTrivia switches the Lexer to lossless mode. Every token carries its
+// exact source text and the whitespace and comments around it, see
+// token.Token. Comments are kept as trivia unless WithComments is also given.
+func WithTrivia() Option {
+	return func(l *Lexer) {
+		l.lossless = true
+	}
+}
+
+// WithDialect sets the keywords and operators the Lexer recognises. Operators
+// that are not part of the dialect are reported as errors. The default is
+// token.Default.
+func WithDialect(d *token.Dialect) Option {
+	return func(l *Lexer) {
+		l.dialect = d
+	}
+}
@@ -0,0 +1,65 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+
+	"github.com/antklim/go-inter/token"
+)
+
+// DefaultBufferSize is the size of the read buffer used by NewReader unless
+// WithBufferSize is given.
+const DefaultBufferSize = 64 * 1024
+
+// NewReader returns a Lexer that reads the source from r. The source is read
+// through a fixed-size buffer, so only the token being scanned and at most one
+// buffer of lookahead are kept in memory. The tokens and positions it produces
+// are the same as New produces for the whole source.
+func NewReader(r io.Reader, opts ...Option) *Lexer {
+	l := newLexer(opts)
+	l.reader = r
+	l.buf = make([]byte, l.bufSize)
+	l.readChar()
+	return l
+}
+
+// fill reads from l.reader until the input up to offset end is buffered or
+// the reader is exhausted. Input before l.mark is discarded.
+func (l *Lexer) fill(end int) {
+	if end <= l.base+len(l.input) {
+		return
+	}
+
+	input := l.input[l.mark-l.base:]
+	for l.reader != nil && l.mark+len(input) < end {
+		n, err := l.reader.Read(l.buf)
+		input += string(l.buf[:n])
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				l.errorf(l.positionAt(input, l.mark+len(input)), "read error: %s", err)
+			}
+			l.reader = nil
+		}
+	}
+
+	l.input = input
+	l.base = l.mark
+}
+
+// positionAt returns the position of the offset end, which lies at or after
+// the current character in input, the buffered text starting at l.mark.
+func (l *Lexer) positionAt(input string, end int) token.Position {
+	pos := l.position()
+	line, lineStart := pos.Line, l.lineStart
+
+	for i := l.chPosition; i < end; i++ {
+		if c := input[i-l.mark]; c == '\n' || c == '\r' && (i+1 == end || input[i+1-l.mark] != '\n') {
+			line++
+			lineStart = i + 1
+		}
+	}
+
+	pos.Offset, pos.Line, pos.Column = end, line, end-lineStart+1
+	return pos
+}
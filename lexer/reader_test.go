@@ -0,0 +1,97 @@
+package lexer_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/token"
+)
+
+var readerCorpus = []string{
+	"",
+	"=+{}(),🤗;!-/ *5 < 10 > 8.,",
+	`let five = 5;
+	let add = fn(x, y) {
+		x + y;
+	};
+	let add_result = add(five, 10);
+	`,
+	"let café = \"h\\u{e9}llo 🤗\"; // comment\n/* block /* nested */ */ π <= 3.14e0 && x != 0x_FF",
+	"🤗🤗🤗🤗 变量 = 1_000; 🤗",
+	"\"unterminated\nlet x = 1e+; 0b102 /* unterminated",
+	"\uFEFF#!/usr/bin/env go-inter\r\nlet x = \"a\xffb\";\rx \xfe\r\n`\r`",
+	"let s = `raw\r\nlines` + \"Hello ${name + \"${'!'}\"} and ${ {x} }\"\nc == 'x'",
+}
+
+func TestNewReader(t *testing.T) {
+	bufferSizes := []int{1, 2, 3, 4, 5, 7, 16, lexer.DefaultBufferSize}
+
+	for i, input := range readerCorpus {
+		want, wantErrs := tokenize(lexer.New(input, lexer.WithFilename("corpus.gi")))
+
+		for _, size := range bufferSizes {
+			readers := map[string]io.Reader{
+				"strings.Reader": strings.NewReader(input),
+				"OneByteReader":  iotest.OneByteReader(strings.NewReader(input)),
+				"HalfReader":     iotest.HalfReader(strings.NewReader(input)),
+			}
+
+			for name, r := range readers {
+				l := lexer.NewReader(r, lexer.WithFilename("corpus.gi"), lexer.WithBufferSize(size))
+				got, gotErrs := tokenize(l)
+
+				if len(got) != len(want) {
+					t.Fatalf("input #%d, %s, buffer size %d: want %d tokens, got %d", i, name, size, len(want), len(got))
+				}
+				for j := range want {
+					if got[j] != want[j] {
+						t.Errorf("input #%d, %s, buffer size %d, token #%d:\n\twant %+v\n\t got %+v", i, name, size, j, want[j], got[j])
+					}
+				}
+
+				if len(gotErrs) != len(wantErrs) {
+					t.Fatalf("input #%d, %s, buffer size %d: want %d errors, got %d", i, name, size, len(wantErrs), len(gotErrs))
+				}
+				for j := range wantErrs {
+					if gotErrs[j] != wantErrs[j] {
+						t.Errorf("input #%d, %s, buffer size %d, error #%d:\n\twant %v\n\t got %v", i, name, size, j, wantErrs[j], gotErrs[j])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNewReaderError(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("\nlet x"), iotest.ErrReader(errors.New("disk on fire")))
+	l := lexer.NewReader(r, lexer.WithBufferSize(1))
+
+	got, errs := tokenize(l)
+
+	if len(got) != 4 || got[0].Type != token.LET || got[1].Type != token.IDENT || got[2].Type != token.SEMICOLON || got[3].Type != token.EOF {
+		t.Errorf("wrong tokens: %+v", got)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("want 1 lexer error, got %d: %v", len(errs), errs)
+	}
+	if want := "2:6: read error: disk on fire"; errs[0].Error() != want {
+		t.Errorf("wrong error: want %q, got %q", want, errs[0].Error())
+	}
+}
+
+// tokenize returns all tokens up to and including EOF and the lexer errors.
+func tokenize(l *lexer.Lexer) ([]token.Token, []lexer.Error) {
+	var toks []token.Token
+	for {
+		tok := l.NextToken()
+		toks = append(toks, tok)
+		if tok.Type == token.EOF {
+			return toks, l.Errors()
+		}
+	}
+}
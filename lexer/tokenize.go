@@ -0,0 +1,56 @@
+package lexer
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/antklim/go-inter/token"
+)
+
+// All returns an iterator over the remaining tokens, not including the final
+// token.EOF.
+func (l *Lexer) All() iter.Seq[token.Token] {
+	return func(yield func(token.Token) bool) {
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			if !yield(tok) {
+				return
+			}
+		}
+	}
+}
+
+// Tokenize returns the tokens of src, not including the final token.EOF. It
+// stops at the first lexical error and returns it together with the tokens
+// that precede the malformed input.
+func Tokenize(src string, opts ...Option) ([]token.Token, error) {
+	l := New(src, opts...)
+
+	var toks []token.Token
+	for tok := range l.All() {
+		if len(l.errors) > 0 {
+			return toks, l.errors[0]
+		}
+		toks = append(toks, tok)
+	}
+
+	if len(l.errors) > 0 {
+		return toks, l.errors[0]
+	}
+
+	return toks, nil
+}
+
+// Source returns the concatenated trivia and source text of toks. For the
+// complete token stream of a Lexer in lossless mode, including the final
+// token.EOF, it reproduces the source byte for byte.
+func Source(toks []token.Token) string {
+	var out strings.Builder
+
+	for _, tok := range toks {
+		out.WriteString(tok.Leading)
+		out.WriteString(tok.Text)
+		out.WriteString(tok.Trailing)
+	}
+
+	return out.String()
+}
@@ -0,0 +1,87 @@
+package lexer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/token"
+)
+
+func TestAll(t *testing.T) {
+	t.Run("yields every token before EOF", func(t *testing.T) {
+		l := lexer.New("let x = 5;")
+
+		var got []token.TokenType
+		for tok := range l.All() {
+			got = append(got, tok.Type)
+		}
+
+		want := []token.TokenType{token.LET, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON}
+		if len(got) != len(want) {
+			t.Fatalf("want %d tokens, got %d: %q", len(want), len(got), got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("token #%d wrong type: want %q, got %q", i, want[i], got[i])
+			}
+		}
+	})
+
+	t.Run("stops when the consumer breaks", func(t *testing.T) {
+		l := lexer.New("a b c")
+
+		for tok := range l.All() {
+			if tok.Literal == "b" {
+				break
+			}
+		}
+
+		if tok := l.NextToken(); tok.Literal != "c" {
+			t.Errorf("wrong next token: want %q, got %q", "c", tok.Literal)
+		}
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	t.Run("valid source", func(t *testing.T) {
+		toks, err := lexer.Tokenize("add(1, 2.5)")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{"add", "(", "1", ",", "2.5", ")", "\n"}
+		if len(toks) != len(want) {
+			t.Fatalf("want %d tokens, got %d: %+v", len(want), len(toks), toks)
+		}
+		for i := range want {
+			if toks[i].Literal != want[i] {
+				t.Errorf("token #%d wrong literal: want %q, got %q", i, want[i], toks[i].Literal)
+			}
+		}
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		toks, err := lexer.Tokenize("let s = \"a\\qb\"; let t = $;", lexer.WithFilename("main.gi"))
+
+		if len(toks) != 3 {
+			t.Errorf("want 3 tokens before the error, got %d: %+v", len(toks), toks)
+		}
+
+		var lexErr lexer.Error
+		if !errors.As(err, &lexErr) {
+			t.Fatalf("error is not lexer.Error, got %T", err)
+		}
+		if want := `main.gi:1:11: unknown escape sequence \q`; err.Error() != want {
+			t.Errorf("wrong error: want %q, got %q", want, err.Error())
+		}
+	})
+
+	t.Run("unexpected character", func(t *testing.T) {
+		_, err := lexer.Tokenize("x & y")
+
+		if want := `1:3: '&' is not an operator — use '&&'`; err == nil || err.Error() != want {
+			t.Errorf("wrong error: want %q, got %v", want, err)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package parser_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/antklim/go-inter/lexer"
+	"github.com/antklim/go-inter/parser"
+)
+
+// syntheticProgram returns a program of n generated statements.
+func syntheticProgram(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "let value_%d = %d + 3.5 * x_%d - 0x%X / 2 %% 7;\n", i, i, i, i)
+		fmt.Fprintf(&b, "a <= b && c >= d || !flag != e == -f;\n")
+		fmt.Fprintf(&b, "total += value_%d |> scale(%d, \"label %d\\n\") 🤗 log; // note\n", i, i, i)
+		fmt.Fprintf(&b, "return add(x_%d, y * 2, z(1, 2 + 3));\n", i)
+	}
+	return b.String()
+}
+
+func BenchmarkParser(b *testing.B) {
+	input := syntheticProgram(1000)
+	b.SetBytes(int64(len(input)))
+
+	for b.Loop() {
+		p := parser.New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			b.Fatalf("parser errors: %v", p.Errors()[:1])
+		}
+	}
+}
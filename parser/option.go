@@ -0,0 +1,14 @@
+package parser
+
+import "github.com/antklim/go-inter/token"
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithDialect sets the dialect of the parsed language. Only the operators of
+// the dialect get parse functions. It should match the dialect of the lexer.
+func WithDialect(d *token.Dialect) Option {
+	return func(p *Parser) {
+		p.dialect = d
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/antklim/go-inter/ast"
 	"github.com/antklim/go-inter/lexer"
@@ -12,23 +13,42 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN
+	PIPE
+	LOGICAL_OR
+	LOGICAL_AND
 	EQUALS
 	LESSGREATER
 	SUM
 	PRODUCT
 	PREFIX
 	CALL
+	INDEX
 )
 
-var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
+// precedences holds the binding power of infix operators, tokens that are not
+// infix operators have 0.
+var precedences = [token.NumTokenTypes]int{
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.HUG:             PIPE,
+	token.OR:              LOGICAL_OR,
+	token.AND:             LOGICAL_AND,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.LT_EQ:           LESSGREATER,
+	token.GT_EQ:           LESSGREATER,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
 }
 
 type (
@@ -37,46 +57,95 @@ type (
 )
 
 type Parser struct {
-	l *lexer.Lexer
+	l       *lexer.Lexer
+	dialect *token.Dialect
 
 	curToken  token.Token
 	peekToken token.Token
 
-	errors []string
+	// The doc comments directly above curToken and peekToken.
+	curDoc  []token.Token
+	peekDoc []token.Token
+
+	// Tokens read from the lexer after peekToken, see tokenAfterPeek.
+	pending []token.Token
 
-	prefixParserFns map[token.TokenType]prefixParserFn
-	infixParserFns  map[token.TokenType]infixParserFn
+	errors []string
+	// Whether the unexpected end of the input has been reported, so that the
+	// parse functions unwinding from it do not report it again.
+	eofReported bool
+	// Whether errors were found while parsing the left operand passed to the
+	// current infix parse function, so the operand may be incomplete.
+	leftIncomplete bool
+
+	prefixParserFns [token.NumTokenTypes]prefixParserFn
+	infixParserFns  [token.NumTokenTypes]infixParserFn
 }
 
+// registerPrefix registers f for t, unless t is an operator that is not part
+// of the dialect.
 func (p *Parser) registerPrefix(t token.TokenType, f prefixParserFn) {
+	if token.IsOperator(t) && !p.dialect.IsOperator(t) {
+		return
+	}
 	p.prefixParserFns[t] = f
 }
 
+// registerInfix registers f for t, unless t is an operator that is not part
+// of the dialect.
 func (p *Parser) registerInfix(t token.TokenType, f infixParserFn) {
+	if token.IsOperator(t) && !p.dialect.IsOperator(t) {
+		return
+	}
 	p.infixParserFns[t] = f
 }
 
-func New(l *lexer.Lexer) *Parser {
+// New returns a Parser that reads tokens from l. Unless WithDialect is given
+// the parser uses the dialect of l.
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:       l,
+		dialect: l.Dialect(),
+		errors:  []string{},
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
 
-	p.prefixParserFns = make(map[token.TokenType]prefixParserFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
+	p.registerPrefix(token.STRING, p.parseStringLiteral)
+	p.registerPrefix(token.STRING_START, p.parseInterpolatedString)
+	p.registerPrefix(token.CHAR, p.parseCharLiteral)
+	p.registerPrefix(token.TRUE, p.parseBoolean)
+	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
+	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 
-	p.infixParserFns = make(map[token.TokenType]infixParserFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.AND, p.parseInfixExpression)
+	p.registerInfix(token.OR, p.parseInfixExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.HUG, p.parsePipeExpression)
+	p.registerInfix(token.LPAREN, p.parseCallExpression)
+	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
 	// Read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -99,28 +168,121 @@ func (p *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// Errors returns the errors found in the input: first the errors of the
+// lexer, then those of the parser.
 func (p *Parser) Errors() []string {
-	return p.errors
+	lexErrs := p.l.Errors()
+	if len(lexErrs) == 0 {
+		return p.errors
+	}
+
+	errs := make([]string, 0, len(lexErrs)+len(p.errors))
+	for _, err := range lexErrs {
+		errs = append(errs, err.Error())
+	}
+	return append(errs, p.errors...)
 }
 
 func (p *Parser) nextToken() {
-	p.curToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	p.curToken, p.curDoc = p.peekToken, p.peekDoc
+
+	// Comments kept by the lexer carry no meaning for the parser. Doc
+	// comments document the next token if they are on the lines directly
+	// above it; a doc comment after a token on the same line does not.
+	var doc []token.Token
+	for {
+		tok := p.readToken()
+		switch tok.Type {
+		case token.COMMENT:
+			continue
+		case token.DOC_COMMENT:
+			if tok.Pos.Line == p.curToken.Pos.Line {
+				continue
+			}
+			if !followsDirectly(doc, tok) {
+				doc = nil
+			}
+			doc = append(doc, tok)
+			continue
+		}
+
+		if !followsDirectly(doc, tok) {
+			doc = nil
+		}
+		p.peekToken, p.peekDoc = tok, doc
+		return
+	}
 }
 
+// readToken returns the next token from the lexer, or the first pending one.
+func (p *Parser) readToken() token.Token {
+	if len(p.pending) == 0 {
+		return p.l.NextToken()
+	}
+	tok := p.pending[0]
+	p.pending = p.pending[1:]
+	return tok
+}
+
+// tokenAfterPeek returns the first token after peekToken that is not a
+// comment, without consuming it.
+func (p *Parser) tokenAfterPeek() token.Token {
+	for _, tok := range p.pending {
+		if tok.Type != token.COMMENT && tok.Type != token.DOC_COMMENT {
+			return tok
+		}
+	}
+	for {
+		tok := p.l.NextToken()
+		p.pending = append(p.pending, tok)
+		if tok.Type != token.COMMENT && tok.Type != token.DOC_COMMENT {
+			return tok
+		}
+	}
+}
+
+// followsDirectly reports whether tok is on the line after the last of the
+// doc comments, or whether there are none.
+func followsDirectly(doc []token.Token, tok token.Token) bool {
+	return len(doc) == 0 || doc[len(doc)-1].Pos.Line+1 == tok.Pos.Line
+}
+
+// docText returns the text of the doc comments, without the slashes and the
+// space after them, one line per comment.
+func docText(doc []token.Token) string {
+	lines := make([]string, len(doc))
+	for i, c := range doc {
+		line := strings.TrimPrefix(c.Literal, "///")
+		lines[i] = strings.TrimPrefix(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseStatement parses the statement at the current token. A '{' that starts
+// a statement opens a block, anywhere else it starts a hash literal.
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
-	case token.LET:
-		return p.parseLetStatement()
+	// A failed statement is returned as an untyped nil, not a nil pointer in
+	// a non-nil interface.
+	case token.LET, token.CONST:
+		if stmt := p.parseLetStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	case token.RETURN:
-		return p.parseReturnStatement()
+		if stmt := p.parseReturnStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.LBRACE:
+		return p.parseBraceStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
-	stmt := &ast.LetStatement{Token: p.curToken}
+	stmt := &ast.LetStatement{Token: p.curToken, Doc: docText(p.curDoc)}
 
 	if !p.expectPeek(token.IDENT) {
 		return nil
@@ -132,25 +294,120 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		return nil
 	}
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	switch p.peekToken.Type {
+	case token.SEMICOLON:
+		p.errorf(p.peekToken.Pos, "missing value in %s statement", stmt.Token.Literal)
+		p.nextToken()
+		return nil
+	case token.EOF:
+		p.unexpectedEOF(p.peekToken)
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
 	return stmt
 }
 
+// parseReturnStatement parses a return statement. The value is optional: a
+// bare return is followed by a semicolon, the end of a block or the end of
+// the input.
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
-	p.nextToken()
+	if !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+		stmt.Value = p.parseExpression(LOWEST)
+	}
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 
 	return stmt
 }
 
+// parseBraceStatement parses a statement that starts with {. It is an
+// expression statement starting with a hash literal if the first expression
+// in the braces is followed by a colon, and a block statement otherwise.
+func (p *Parser) parseBraceStatement() ast.Statement {
+	switch p.peekToken.Type {
+	case token.RBRACE, token.LBRACE, token.LET, token.CONST, token.RETURN, token.EOF:
+		if block := p.parseBlockStatement(); block != nil {
+			return block
+		}
+		return nil
+	}
+
+	brace := p.curToken
+	errs := p.errorCount()
+	p.nextToken()
+	first := &ast.ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+
+	if p.peekTokenIs(token.COLON) {
+		hash := p.parseHashPairs(&ast.HashLiteral{Token: brace, Pairs: []ast.HashPair{}}, first.Expression)
+		stmt := &ast.ExpressionStatement{Token: brace, Expression: p.parseInfixExpressions(LOWEST, hash, errs)}
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+
+		return stmt
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	p.nextToken()
+
+	block := &ast.BlockStatement{Token: brace, Statements: []ast.Statement{first}}
+	if p.parseBlockStatements(block) == nil {
+		return nil
+	}
+
+	return block
+}
+
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	block := &ast.BlockStatement{Token: p.curToken}
+	block.Statements = []ast.Statement{}
+
+	p.nextToken()
+
+	return p.parseBlockStatements(block)
+}
+
+// parseBlockStatements parses the statements of block from the current token
+// up to the closing brace.
+func (p *Parser) parseBlockStatements(block *ast.BlockStatement) *ast.BlockStatement {
+	for !p.curTokenIs(token.RBRACE) {
+		if p.curTokenIs(token.EOF) {
+			// An error inside the block may already have reported the end
+			// of the input.
+			if !p.eofReported {
+				p.eofReported = true
+				p.errorf(block.Token.Pos, "block not terminated")
+			}
+			return nil
+		}
+		if stmt := p.parseStatement(); stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return block
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 	stmt.Expression = p.parseExpression(LOWEST)
@@ -162,19 +419,54 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return stmt
 }
 
-func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	p.errors = append(p.errors, fmt.Sprintf("no prefix parse function for %s found", t))
+// errorCount returns the number of errors found so far by the lexer and the
+// parser.
+func (p *Parser) errorCount() int {
+	return len(p.l.Errors()) + len(p.errors)
+}
+
+// errorf records an error message prefixed with the source position pos.
+func (p *Parser) errorf(pos token.Position, format string, args ...any) {
+	p.errors = append(p.errors, pos.String()+": "+fmt.Sprintf(format, args...))
+}
+
+// unexpectedEOF records that the input ended in the middle of a statement.
+// Only the first occurrence is reported.
+func (p *Parser) unexpectedEOF(t token.Token) {
+	if p.eofReported {
+		return
+	}
+	p.eofReported = true
+	p.errorf(t.Pos, "unexpected end of input")
+}
+
+func (p *Parser) noPrefixParseFnError(t token.Token) {
+	switch t.Type {
+	case token.ILLEGAL:
+		// The lexer has already reported why the token is illegal.
+	case token.EOF:
+		p.unexpectedEOF(t)
+	default:
+		p.errorf(t.Pos, "no prefix parse function for %s found", t.Type)
+	}
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParserFns[p.curToken.Type]
 	if prefix == nil {
-		p.noPrefixParseFnError(p.curToken.Type)
+		p.noPrefixParseFnError(p.curToken)
 		return nil
 	}
 
-	leftExp := prefix()
+	errs := p.errorCount()
 
+	return p.parseInfixExpressions(precedence, prefix(), errs)
+}
+
+// parseInfixExpressions parses the infix operators that follow leftExp and
+// bind tighter than precedence. errs is the error count before leftExp was
+// parsed.
+func (p *Parser) parseInfixExpressions(precedence int, leftExp ast.Expression, errs int) ast.Expression {
 	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
 		infix := p.infixParserFns[p.peekToken.Type]
 		if infix == nil {
@@ -182,6 +474,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		}
 
 		p.nextToken()
+		p.leftIncomplete = p.errorCount() != errs
 		leftExp = infix(leftExp)
 	}
 
@@ -206,7 +499,85 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
-		p.errors = append(p.errors, fmt.Sprintf("failed to parse integer literal: %s", err.Error()))
+		p.errorf(p.curToken.Pos, "failed to parse integer literal: %s", err.Error())
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{Token: p.curToken}
+
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		p.errorf(p.curToken.Pos, "failed to parse float literal: %s", err.Error())
+		return nil
+	}
+
+	// ParseFloat silently rounds values too small for a float64 to zero.
+	mantissa, _, _ := strings.Cut(strings.ToLower(p.curToken.Literal), "e")
+	if value == 0 && strings.Trim(mantissa, "0._") != "" {
+		p.errorf(p.curToken.Pos, "failed to parse float literal: %q underflows float64", p.curToken.Literal)
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	lit := &ast.StringLiteral{Token: p.curToken}
+
+	value, err := lexer.Unquote(p.curToken.Literal)
+	if err != nil {
+		p.errorf(p.curToken.Pos, "failed to parse string literal: %s", err.Error())
+		return nil
+	}
+
+	lit.Value = value
+	return lit
+}
+
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+
+	for {
+		value, err := lexer.Unquote(p.curToken.Literal)
+		if err != nil {
+			p.errorf(p.curToken.Pos, "failed to parse string literal: %s", err.Error())
+			return nil
+		}
+		if value != "" {
+			str.Parts = append(str.Parts, &ast.StringLiteral{Token: p.curToken, Value: value})
+		}
+
+		if p.curTokenIs(token.STRING_END) {
+			return str
+		}
+
+		p.nextToken()
+		if p.curTokenIs(token.STRING_MID) || p.curTokenIs(token.STRING_END) {
+			p.errorf(p.curToken.Pos, "empty interpolation in string literal")
+			return nil
+		}
+		str.Parts = append(str.Parts, p.parseExpression(LOWEST))
+
+		if !p.peekTokenIs(token.STRING_MID) && !p.peekTokenIs(token.STRING_END) {
+			p.peekError(token.STRING_END)
+			return nil
+		}
+		p.nextToken()
+	}
+}
+
+func (p *Parser) parseCharLiteral() ast.Expression {
+	lit := &ast.CharLiteral{Token: p.curToken}
+
+	value, err := lexer.UnquoteChar(p.curToken.Literal)
+	if err != nil {
+		p.errorf(p.curToken.Pos, "failed to parse character literal: %s", err.Error())
 		return nil
 	}
 
@@ -214,6 +585,24 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseBoolean() ast.Expression {
+	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
+}
+
+// parseGroupedExpression parses an expression in parentheses. The grouping
+// leaves no node in the AST, it only overrides the precedence.
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.nextToken()
+
+	expr := p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return expr
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expr := &ast.InfixExpression{
 		Token:    p.curToken,
@@ -227,6 +616,148 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expr
 }
 
+// parseAssignExpression parses a compound assignment such as x += 1. Unlike
+// other infix operators assignments are right-associative, so a -= b += 1
+// assigns to b first.
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	expr := &ast.InfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	if _, ok := left.(*ast.Identifier); left != nil && !ok {
+		// String would dereference the missing parts of an incomplete left.
+		if p.leftIncomplete {
+			p.errorf(p.curToken.Pos, "cannot assign to this expression")
+		} else {
+			p.errorf(p.curToken.Pos, "cannot assign to %s", left.String())
+		}
+	}
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expr.Right = p.parseExpression(precedence - 1)
+	return expr
+}
+
+// parsePipeExpression parses a pipeline left 🤗 f(args). The target must be
+// a function call or a function name.
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	expr := &ast.PipeExpression{Token: p.curToken, Left: left}
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	errs := p.errorCount()
+	expr.Right = p.parseExpression(precedence)
+
+	// A target with errors may be incomplete, the errors already explain it.
+	if p.errorCount() != errs {
+		return nil
+	}
+
+	switch expr.Right.(type) {
+	case *ast.CallExpression, *ast.Identifier:
+	case nil:
+		return nil
+	default:
+		p.errorf(expr.Token.Pos, "pipeline target must be a function call or name, got %s", expr.Right.String())
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	expr := &ast.CallExpression{Token: p.curToken, Function: function}
+	expr.Arguments = p.parseExpressionList(token.RPAREN)
+	return expr
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expr := &ast.IndexExpression{Token: p.curToken, Left: left}
+
+	p.nextToken()
+	expr.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken}
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	return array
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken}
+	hash.Pairs = []ast.HashPair{}
+
+	if p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		return hash
+	}
+
+	p.nextToken()
+	return p.parseHashPairs(hash, p.parseExpression(LOWEST))
+}
+
+// parseHashPairs parses the pairs of hash up to the closing brace. The
+// current token is the end of key, the key of the first pair.
+func (p *Parser) parseHashPairs(hash *ast.HashLiteral, key ast.Expression) ast.Expression {
+	for {
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+		if p.peekTokenIs(token.RBRACE) {
+			p.nextToken()
+			return hash
+		}
+
+		p.nextToken()
+		key = p.parseExpression(LOWEST)
+	}
+}
+
+// parseExpressionList parses a comma separated list of expressions up to the
+// end token. The current token is the one that opens the list.
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if p.peekTokenIs(end) {
+		p.nextToken()
+		return list
+	}
+
+	p.nextToken()
+	list = append(list, p.parseExpression(LOWEST))
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		p.nextToken()
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.expectPeek(end) {
+		return nil
+	}
+
+	return list
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -246,13 +777,28 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead",
+	if p.peekTokenIs(token.EOF) {
+		p.unexpectedEOF(p.peekToken)
+		return
+	}
+
+	// The lexer inserts a semicolon at a line break or the end of the input,
+	// the user did not write it.
+	if p.peekTokenIs(token.SEMICOLON) && p.peekToken.Literal == "\n" {
+		if next := p.tokenAfterPeek(); next.Type == token.EOF {
+			p.unexpectedEOF(next)
+		} else {
+			p.errorf(p.peekToken.Pos, "expected next token to be %s, got newline instead", t)
+		}
+		return
+	}
+
+	p.errorf(p.peekToken.Pos, "expected next token to be %s, got %s instead",
 		t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
 }
 
 func (p *Parser) peekPrecedence() int {
-	if p, ok := precedences[p.peekToken.Type]; ok {
+	if p := precedences[p.peekToken.Type]; p != 0 {
 		return p
 	}
 
@@ -260,7 +806,7 @@ func (p *Parser) peekPrecedence() int {
 }
 
 func (p *Parser) curPrecedence() int {
-	if p, ok := precedences[p.curToken.Type]; ok {
+	if p := precedences[p.curToken.Type]; p != 0 {
 		return p
 	}
 
@@ -3,17 +3,19 @@ package parser_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/antklim/go-inter/ast"
 	"github.com/antklim/go-inter/lexer"
 	"github.com/antklim/go-inter/parser"
+	"github.com/antklim/go-inter/token"
 )
 
 func TestParseLetStatements(t *testing.T) {
 	input := `
 let x = 5;
-let y = 10;
-let foobar = 838383;
+let y = x;
+let foobar = y * 2 + 838383;
 `
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -26,44 +28,84 @@ let foobar = 838383;
 	}
 
 	testCases := []struct {
-		want string
+		want          string
+		expectedValue string
 	}{
-		{"x"},
-		{"y"},
-		{"foobar"},
+		{"x", "5"},
+		{"y", "x"},
+		{"foobar", "((y * 2) + 838383)"},
 	}
 
 	for i, tc := range testCases {
 		stmt := program.Statements[i]
 		testLetStatement(t, stmt, tc.want)
+
+		value := stmt.(*ast.LetStatement).Value
+		if value == nil {
+			t.Errorf("test #%d letStmt.Value is nil", i)
+			continue
+		}
+		if value.String() != tc.expectedValue {
+			t.Errorf("test #%d wrong value: want %s, got %s", i, tc.expectedValue, value.String())
+		}
+	}
+
+	if want, got := "let x = 5;let y = x;let foobar = ((y * 2) + 838383);", program.String(); want != got {
+		t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
 	}
 }
 
 func TestParseReturnStatements(t *testing.T) {
 	input := `
 return 5;
-return 10;
-return 838383;
-`
+return x;
+return add(x, 838383);
+return
+{ return }
+return`
 	l := lexer.New(input)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
 
-	if len(program.Statements) != 3 {
-		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	if len(program.Statements) != 6 {
+		t.Fatalf("program.Statements does not contain 6 statements. got=%d", len(program.Statements))
 	}
 
-	for _, stmt := range program.Statements {
-		returnStmt, ok := stmt.(*ast.ReturnStatement)
+	testCases := []struct {
+		stmt          ast.Statement
+		expectedValue string
+	}{
+		{program.Statements[0], "5"},
+		{program.Statements[1], "x"},
+		{program.Statements[2], "add(x, 838383)"},
+		{program.Statements[3], ""},
+		{program.Statements[5], ""},
+	}
+
+	for i, tc := range testCases {
+		returnStmt, ok := tc.stmt.(*ast.ReturnStatement)
 		if !ok {
-			t.Errorf("stmt not *ast.ReturnStatement. got=%T", stmt)
+			t.Errorf("stmt not *ast.ReturnStatement. got=%T", tc.stmt)
+			continue
 		}
 		if returnStmt.TokenLiteral() != "return" {
 			t.Errorf("returnStmt.TokenLiteral not 'return', got %q",
 				returnStmt.TokenLiteral())
 		}
+
+		var value string
+		if returnStmt.Value != nil {
+			value = returnStmt.Value.String()
+		}
+		if value != tc.expectedValue {
+			t.Errorf("test #%d wrong value: want %q, got %q", i, tc.expectedValue, value)
+		}
+	}
+
+	if want, got := "return 5;return x;return add(x, 838383);return;{return;}return;", program.String(); want != got {
+		t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
 	}
 }
 
@@ -130,14 +172,292 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestIntegerLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF;", 255},
+		{"0o17;", 15},
+		{"017;", 15},
+		{"0b1010;", 10},
+		{"1_000_000;", 1000000},
+		{"0x_7FFF_FFFF_FFFF_FFFF;", 9223372036854775807},
+	}
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements, got %d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.IntegerLiteral, got %T", stmt.Expression)
+		}
+		if want, got := tt.expected, literal.Value; want != got {
+			t.Errorf("input %q: invalid literal.Value\n\twant %d\n\t got %d", tt.input, want, got)
+		}
+	}
+}
+
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{".5;", 0.5},
+		{"1e-9;", 1e-9},
+		{"0.0;", 0},
+		{"0_0.0;", 0},
+		{"0.0_0;", 0},
+		{"0_0e1;", 0},
+	}
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements, got %d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.FloatLiteral, got %T", stmt.Expression)
+		}
+		if want, got := tt.expected, literal.Value; want != got {
+			t.Errorf("invalid literal.Value\n\twant %g\n\t got %g", want, got)
+		}
+		if want, got := tt.input[:len(tt.input)-1], literal.TokenLiteral(); want != got {
+			t.Errorf("invalid literal.TokenLiteral\n\twant %s\n\t got %s", want, got)
+		}
+	}
+}
+
+func TestFloatLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1e400;", `1:1: failed to parse float literal: strconv.ParseFloat: parsing "1e400": value out of range`},
+		{"1e-400;", `1:1: failed to parse float literal: "1e-400" underflows float64`},
+	}
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errs := p.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("input %q: want 1 error, got %d: %q", tt.input, len(errs), errs)
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("input %q: wrong error\n\twant: %s\n\t got: %s", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestStringLiteralExpression(t *testing.T) {
+	input := `"hello \"world\"\n";`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements, got %d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.StringLiteral, got %T", stmt.Expression)
+	}
+	if want, got := "hello \"world\"\n", literal.Value; want != got {
+		t.Errorf("invalid literal.Value\n\twant %q\n\t got %q", want, got)
+	}
+	if want, got := `"hello \"world\"\n"`, program.String(); want != got {
+		t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	t.Run("parts", func(t *testing.T) {
+		input := `"Hello ${name}, ${a + b}!"`
+
+		l := lexer.New(input)
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has not enough statements, got %d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+		}
+
+		str, ok := stmt.Expression.(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("stmt.Expression is not *ast.InterpolatedString, got %T", stmt.Expression)
+		}
+		if len(str.Parts) != 5 {
+			t.Fatalf("str.Parts does not contain 5 parts. got=%d", len(str.Parts))
+		}
+
+		for i, want := range []string{"Hello ", ", ", "!"} {
+			part := str.Parts[2*i]
+			literal, ok := part.(*ast.StringLiteral)
+			if !ok {
+				t.Fatalf("str.Parts[%d] is not *ast.StringLiteral, got %T", 2*i, part)
+			}
+			if literal.Value != want {
+				t.Errorf("invalid str.Parts[%d].Value\n\twant %q\n\t got %q", 2*i, want, literal.Value)
+			}
+		}
+		for i, want := range []string{"name", "(a + b)"} {
+			if got := str.Parts[2*i+1].String(); got != want {
+				t.Errorf("invalid str.Parts[%d].String()\n\twant %s\n\t got %s", 2*i+1, want, got)
+			}
+		}
+	})
+
+	t.Run("canonical form", func(t *testing.T) {
+		testCases := []struct {
+			input    string
+			expected string
+		}{
+			{`"${x}"`, `"${x}"`},
+			{`"a${x}b${y}c"`, `"a${x}b${y}c"`},
+			{`"tab\t${x}\u{48}\$"`, `"tab\t${x}H$"`},
+			{`"\${x} ${"${y}" 🤗 f}"`, `"\${x} ${f("${y}")}"`},
+			{"\"${`raw`}\"", "\"${`raw`}\""},
+			{`"${a}" + "${ b }"`, `("${a}" + "${b}")`},
+		}
+
+		for _, tC := range testCases {
+			l := lexer.New(tC.input)
+			p := parser.New(l)
+
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+
+			if got := program.String(); got != tC.expected {
+				t.Errorf("invalid program.String()\n\twant %s\n\t got %s", tC.expected, got)
+			}
+		}
+	})
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	testCases := []struct {
+		input         string
+		expectedError string
+	}{
+		{input: `"a ${} b"`, expectedError: "1:6: empty interpolation in string literal"},
+		{input: `"a ${x y} b"`, expectedError: "1:8: expected next token to be STRING_END, got IDENT instead"},
+	}
+
+	for _, tC := range testCases {
+		l := lexer.New(tC.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errs := p.Errors()
+		if len(errs) == 0 {
+			t.Fatalf("%q: expected parser errors", tC.input)
+		}
+		if errs[0] != tC.expectedError {
+			t.Errorf("%q: wrong error: want %q, got %q", tC.input, tC.expectedError, errs[0])
+		}
+	}
+}
+
+func TestCharLiteralExpression(t *testing.T) {
+	input := `c == '\n' || c == 'é'`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program has not enough statements, got %d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	or, ok := stmt.Expression.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.InfixExpression, got %T", stmt.Expression)
+	}
+
+	for i, want := range []rune{'\n', 'é'} {
+		side := []ast.Expression{or.Left, or.Right}[i]
+		eq, ok := side.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("operand #%d is not *ast.InfixExpression, got %T", i, side)
+		}
+		literal, ok := eq.Right.(*ast.CharLiteral)
+		if !ok {
+			t.Fatalf("operand #%d right is not *ast.CharLiteral, got %T", i, eq.Right)
+		}
+		if literal.Value != want {
+			t.Errorf("invalid literal.Value\n\twant %q\n\t got %q", want, literal.Value)
+		}
+	}
+
+	if want, got := `((c == '\n') || (c == 'é'))`, program.String(); want != got {
+		t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
-		input        string
-		operator     string
-		integerValue int64
+		input    string
+		operator string
+		value    any
 	}{
 		{"!5;", "!", 5},
 		{"-15;", "-", 15},
+		{"!true;", "!", true},
+		{"!false;", "!", false},
 	}
 	for _, tt := range prefixTests {
 		l := lexer.New(tt.input)
@@ -159,7 +479,7 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		if exp.Operator != tt.operator {
 			t.Fatalf("exp.Operator is not '%s', got=%s", tt.operator, exp.Operator)
 		}
-		if !testIntegerLiteral(t, exp.Right, tt.integerValue) {
+		if !testLiteralExpression(t, exp.Right, tt.value) {
 			return
 		}
 	}
@@ -168,9 +488,9 @@ func TestParsingPrefixExpressions(t *testing.T) {
 func TestParsingInfixExpressions(t *testing.T) {
 	infixTests := []struct {
 		input      string
-		leftValue  int64
+		leftValue  any
 		operator   string
-		rightValue int64
+		rightValue any
 	}{
 		{"5 + 5;", 5, "+", 5},
 		{"5 - 5;", 5, "-", 5},
@@ -180,6 +500,14 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
+		{"5 && 5;", 5, "&&", 5},
+		{"5 || 5;", 5, "||", 5},
+		{"true == true", true, "==", true},
+		{"true != false", true, "!=", false},
+		{"false && x", false, "&&", "x"},
 	}
 	for _, tt := range infixTests {
 		l := lexer.New(tt.input)
@@ -198,13 +526,13 @@ func TestParsingInfixExpressions(t *testing.T) {
 		if !ok {
 			t.Fatalf("exp is not ast.InfixExpression, got %T", stmt.Expression)
 		}
-		if !testIntegerLiteral(t, exp.Left, tt.leftValue) {
+		if !testLiteralExpression(t, exp.Left, tt.leftValue) {
 			return
 		}
 		if exp.Operator != tt.operator {
 			t.Fatalf("exp.Operator is not '%s', got %s", tt.operator, exp.Operator)
 		}
-		if !testIntegerLiteral(t, exp.Right, tt.rightValue) {
+		if !testLiteralExpression(t, exp.Right, tt.rightValue) {
 			return
 		}
 	}
@@ -263,42 +591,889 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"3 + 4 * 5 == 3 * 1 + 4 * 5",
 			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
 		},
+		{
+			"a + b % c",
+			"(a + (b % c))",
+		},
+		{
+			"a <= b == c >= d",
+			"((a <= b) == (c >= d))",
+		},
+		{
+			"a == b && c != d",
+			"((a == b) && (c != d))",
+		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"x += a || b",
+			"(x += (a || b))",
+		},
+		{
+			"x -= y *= 2 + 3",
+			"(x -= (y *= (2 + 3)))",
+		},
+		{
+			"x /= 2",
+			"(x /= 2)",
+		},
+		{
+			"a + add(b * c) + d",
+			"((a + add((b * c))) + d)",
+		},
+		{
+			"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8))",
+			"add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))",
+		},
+		{
+			"a 🤗 f(b)",
+			"f(a, b)",
+		},
+		{
+			"a |> f",
+			"f(a)",
+		},
+		{
+			"a |> f(1) 🤗 g(2, 3) |> h",
+			"h(g(f(a, 1), 2, 3))",
+		},
+		{
+			"x + 1 |> f(y * 2)",
+			"f((x + 1), (y * 2))",
+		},
+		{
+			"a || b |> f",
+			"f((a || b))",
+		},
+		{
+			"x += a |> f",
+			"(x += f(a))",
+		},
+		{
+			"a * [1, 2, 3, 4][b * c] * d",
+			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
+		},
+		{
+			"add(a * b[2], b[1], 2 * [1, 2][1])",
+			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
+		},
+		{
+			"f(x)[0](y)",
+			"(f(x)[0])(y)",
+		},
+		{
+			"-a[0]",
+			"(-(a[0]))",
+		},
+		{
+			"true",
+			"true",
+		},
+		{
+			"3 > 5 == false",
+			"((3 > 5) == false)",
+		},
+		{
+			"!true != false",
+			"((!true) != false)",
+		},
+		{
+			"1 + (2 + 3) + 4",
+			"((1 + (2 + 3)) + 4)",
+		},
+		{
+			"(5 + 5) * 2",
+			"((5 + 5) * 2)",
+		},
+		{
+			"2 / (5 + 5)",
+			"(2 / (5 + 5))",
+		},
+		{
+			"-(5 + 5)",
+			"(-(5 + 5))",
+		},
+		{
+			"!(true == true)",
+			"(!(true == true))",
+		},
+		{
+			"(a || b) && c",
+			"((a || b) && c)",
+		},
+		{
+			"((a))[(0)]",
+			"(a[0])",
+		},
+		{
+			"(a |> f) + 1",
+			"(f(a) + 1)",
+		},
 	}
-	for _, tt := range tests {
-		l := lexer.New(tt.input)
-		p := parser.New(l)
+	for _, d := range []*token.Dialect{token.Default, scriptingDialect()} {
+		for _, tt := range tests {
+			l := lexer.New(tt.input, lexer.WithDialect(d))
+			p := parser.New(l)
 
-		program := p.ParseProgram()
-		checkParserErrors(t, p)
-		actual := program.String()
+			program := p.ParseProgram()
+			checkParserErrors(t, p)
+			actual := program.String()
 
-		if actual != tt.expected {
-			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+			if actual != tt.expected {
+				t.Errorf("expected=%q, got=%q", tt.expected, actual)
+			}
 		}
 	}
 }
 
-func testLetStatement(t *testing.T, s ast.Statement, name string) {
-	t.Helper()
+func TestDialects(t *testing.T) {
+	input := `
+const answer = 42;
+let five = 5;
+`
+	t.Run("default dialect", func(t *testing.T) {
+		l := lexer.New(input)
+		p := parser.New(l)
+		p.ParseProgram()
 
-	if s.TokenLiteral() != "let" {
-		t.Errorf("s.TokenLiteral not 'let', got=%q", s.TokenLiteral())
-	}
+		// const is an identifier, so "const answer" is not a statement.
+		if len(p.Errors()) == 0 {
+			t.Errorf("want parser errors for const in the default dialect")
+		}
+	})
 
-	letStmt, ok := s.(*ast.LetStatement)
-	if !ok {
-		t.Errorf("s not *ast.LetStatement, got=%T", s)
-	}
+	t.Run("scripting dialect", func(t *testing.T) {
+		d := scriptingDialect()
+		l := lexer.New(input, lexer.WithDialect(d))
+		p := parser.New(l, parser.WithDialect(d))
 
-	if letStmt.Name.Value != name {
-		t.Errorf("letStmt.Name.Value not '%s', got=%s", name, letStmt.Name.Value)
-	}
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 2 {
+			t.Fatalf("program.Statements does not contain 2 statements. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not *ast.LetStatement, got=%T", program.Statements[0])
+		}
+		if stmt.TokenLiteral() != "const" || stmt.Name.Value != "answer" {
+			t.Errorf("wrong const statement: %s %s", stmt.TokenLiteral(), stmt.Name.Value)
+		}
+		testLetStatement(t, program.Statements[1], "five")
+	})
+
+	t.Run("dialect without an operator", func(t *testing.T) {
+		keywords := token.Default.Keywords()
+		var ops []token.TokenType
+		for _, op := range token.Default.Operators() {
+			if op != token.PERCENT {
+				ops = append(ops, op)
+			}
+		}
+		d := token.NewDialect(keywords, ops)
+
+		l := lexer.New("a % b", lexer.WithDialect(d))
+		p := parser.New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("want parser errors for an operator outside of the dialect")
+		}
+	})
+}
+
+// scriptingDialect returns the default dialect extended with while and const.
+func scriptingDialect() *token.Dialect {
+	keywords := token.Default.Keywords()
+	keywords["while"] = token.WHILE
+	keywords["const"] = token.CONST
+	return token.NewDialect(keywords, token.Default.Operators())
+}
+
+func TestCallExpressionParsing(t *testing.T) {
+	input := "add(1, 2 * 3, x);"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement, got %d", len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.CallExpression, got %T", stmt.Expression)
+	}
+	if exp.Function.String() != "add" {
+		t.Errorf("exp.Function is not 'add', got %s", exp.Function.String())
+	}
+	if len(exp.Arguments) != 3 {
+		t.Fatalf("wrong number of arguments, got %d", len(exp.Arguments))
+	}
+	testIntegerLiteral(t, exp.Arguments[0], 1)
+	if want, got := "(2 * 3)", exp.Arguments[1].String(); want != got {
+		t.Errorf("wrong argument #1, want %s, got %s", want, got)
+	}
+	if want, got := "x", exp.Arguments[2].String(); want != got {
+		t.Errorf("wrong argument #2, want %s, got %s", want, got)
+	}
+}
+
+func TestParsingArrayLiterals(t *testing.T) {
+	input := "[1, 2 * 2, x + 3]"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.ArrayLiteral, got %T", stmt.Expression)
+	}
+	if len(array.Elements) != 3 {
+		t.Fatalf("wrong number of elements, got %d", len(array.Elements))
+	}
+	testIntegerLiteral(t, array.Elements[0], 1)
+	if want, got := "(2 * 2)", array.Elements[1].String(); want != got {
+		t.Errorf("wrong element #1, want %s, got %s", want, got)
+	}
+	if want, got := "(x + 3)", array.Elements[2].String(); want != got {
+		t.Errorf("wrong element #2, want %s, got %s", want, got)
+	}
+}
+
+func TestParsingEmptyArrayLiteral(t *testing.T) {
+	input := "[]"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.ArrayLiteral, got %T", stmt.Expression)
+	}
+	if len(array.Elements) != 0 {
+		t.Errorf("wrong number of elements, got %d", len(array.Elements))
+	}
+}
+
+func TestParsingIndexExpressions(t *testing.T) {
+	input := "myArray[1 + 1]"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	indexExp, ok := stmt.Expression.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.IndexExpression, got %T", stmt.Expression)
+	}
+	if want, got := "myArray", indexExp.Left.String(); want != got {
+		t.Errorf("wrong left, want %s, got %s", want, got)
+	}
+	if want, got := "(1 + 1)", indexExp.Index.String(); want != got {
+		t.Errorf("wrong index, want %s, got %s", want, got)
+	}
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected map[string]string
+	}{
+		{`{"one": 1, "two": 2, "three": 3}`, map[string]string{`"one"`: "1", `"two"`: "2", `"three"`: "3"}},
+		{`{}`, map[string]string{}},
+		{`{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`, map[string]string{`"one"`: "(0 + 1)", `"two"`: "(10 - 8)", `"three"`: "(15 / 5)"}},
+		{`{1: y, x: [y]}`, map[string]string{"1": "y", "x": "[y]"}},
+	}
+	for _, tt := range tests {
+		// A '{' that starts a statement is a block, so the hash is an argument.
+		l := lexer.New("f(" + tt.input + ")")
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got %T", program.Statements[0])
+		}
+
+		call, ok := stmt.Expression.(*ast.CallExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.CallExpression, got %T", stmt.Expression)
+		}
+
+		hash, ok := call.Arguments[0].(*ast.HashLiteral)
+		if !ok {
+			t.Fatalf("call.Arguments[0] is not ast.HashLiteral, got %T", call.Arguments[0])
+		}
+		if len(hash.Pairs) != len(tt.expected) {
+			t.Fatalf("input %q: wrong number of pairs, got %d", tt.input, len(hash.Pairs))
+		}
+		for _, pair := range hash.Pairs {
+			if want, got := tt.expected[pair.Key.String()], pair.Value.String(); want != got {
+				t.Errorf("input %q: wrong value for key %s, want %s, got %s", tt.input, pair.Key, want, got)
+			}
+		}
+	}
+}
+
+func TestBlockAndHashLiteral(t *testing.T) {
+	input := `
+{ "k" }
+f({ "k": v })
+{
+	x
+	{}
+}
+{"k": v}
+{"a": 1, "b": 2,}["a"] + 1
+{ x; {"k": 1} }`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 6 {
+		t.Fatalf("program.Statements does not contain 6 statements. got=%d", len(program.Statements))
+	}
+
+	for _, i := range []int{0, 2, 5} {
+		if _, ok := program.Statements[i].(*ast.BlockStatement); !ok {
+			t.Errorf("program.Statements[%d] is not *ast.BlockStatement. got=%T", i, program.Statements[i])
+		}
+	}
+	call := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	if _, ok := call.Arguments[0].(*ast.HashLiteral); !ok {
+		t.Errorf("call.Arguments[0] is not *ast.HashLiteral. got=%T", call.Arguments[0])
+	}
+	if stmt, ok := program.Statements[3].(*ast.ExpressionStatement); !ok {
+		t.Errorf("program.Statements[3] is not *ast.ExpressionStatement. got=%T", program.Statements[3])
+	} else if _, ok := stmt.Expression.(*ast.HashLiteral); !ok {
+		t.Errorf("stmt.Expression is not *ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if want, got := `{"k"}f({"k": v}){x{}}{"k": v}(({"a": 1, "b": 2}["a"]) + 1){x{"k": 1}}`, program.String(); want != got {
+		t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
+	}
+}
+
+func TestPipeExpressionParsing(t *testing.T) {
+	input := "a 🤗 f(b);"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got %T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.PipeExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.PipeExpression, got %T", stmt.Expression)
+	}
+	if want, got := "a", exp.Left.String(); want != got {
+		t.Errorf("wrong exp.Left, want %s, got %s", want, got)
+	}
+	if want, got := "f(b)", exp.Right.String(); want != got {
+		t.Errorf("wrong exp.Right, want %s, got %s", want, got)
+	}
+
+	call := exp.Call()
+	if want, got := "f", call.Function.String(); want != got {
+		t.Errorf("wrong call.Function, want %s, got %s", want, got)
+	}
+	if len(call.Arguments) != 2 {
+		t.Fatalf("wrong number of call arguments, got %d", len(call.Arguments))
+	}
+}
+
+func TestParsingWithComments(t *testing.T) {
+	input := `
+// sum of two numbers
+a + /* inline */ b; // trailing
+`
+	for _, l := range []*lexer.Lexer{lexer.New(input), lexer.New(input, lexer.WithComments())} {
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if want, got := "(a + b)", program.String(); want != got {
+			t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
+		}
+	}
+}
+
+func TestNewlineTerminatedStatements(t *testing.T) {
+	input := `
+let x = 5
+let y = x; return y
+add(x, y)
+x + y`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 5 {
+		t.Fatalf("program.Statements does not contain 5 statements. got=%d", len(program.Statements))
+	}
+
+	testLetStatement(t, program.Statements[0], "x")
+	testLetStatement(t, program.Statements[1], "y")
+	if _, ok := program.Statements[2].(*ast.ReturnStatement); !ok {
+		t.Errorf("program.Statements[2] is not *ast.ReturnStatement. got=%T", program.Statements[2])
+	}
+	for i, want := range []string{"add(x, y)", "(x + y)"} {
+		stmt := program.Statements[i+3]
+		if stmt.String() != want {
+			t.Errorf("program.Statements[%d] wrong String(): want %s, got %s", i+3, want, stmt.String())
+		}
+	}
+}
+
+func TestDocComments(t *testing.T) {
+	input := `
+/// computes the average
+///   of a and b
+let avg = 1;
+
+let undocumented = 2; /// trailing
+
+/// separated by a blank line
+
+let x = 3;
+// not a doc comment
+/// doc comment
+let y = 4;
+/// not on let
+{
+	/// nested
+	let z = 5;
+}
+/// malformed
+let w = ;
+{ let v = ; }`
+
+	for _, l := range []*lexer.Lexer{lexer.New(input), lexer.New(input, lexer.WithComments())} {
+		p := parser.New(l)
+
+		program := p.ParseProgram()
+
+		want := []string{
+			"20:9: missing value in let statement",
+			"21:11: missing value in let statement",
+		}
+		if errs := p.Errors(); fmt.Sprint(errs) != fmt.Sprint(want) {
+			t.Errorf("wrong parser errors\n\twant %q\n\t got %q", want, errs)
+		}
+		if want, got := "let avg = 1;let undocumented = 2;let x = 3;let y = 4;{let z = 5;}{}", program.String(); want != got {
+			t.Errorf("invalid program.String()\n\twant %s\n\t got %s", want, got)
+		}
+
+		tests := []struct {
+			name string
+			doc  string
+		}{
+			{"avg", "computes the average\n  of a and b"},
+			{"undocumented", ""},
+			{"x", ""},
+			{"y", "doc comment"},
+		}
+		for i, tt := range tests {
+			stmt := program.Statements[i]
+			testLetStatement(t, stmt, tt.name)
+			if got := stmt.(*ast.LetStatement).Doc; got != tt.doc {
+				t.Errorf("wrong doc of %s\n\twant %q\n\t got %q", tt.name, tt.doc, got)
+			}
+		}
+
+		documented := program.Documented()
+		if len(documented) != 2 {
+			t.Fatalf("program.Documented() does not contain 2 statements. got=%d", len(documented))
+		}
+		for i, want := range []string{"avg", "y"} {
+			if got := documented[i].Name.Value; got != want {
+				t.Errorf("wrong documented binding #%d: want %s, got %s", i, want, got)
+			}
+		}
+	}
+}
+
+func TestParserErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			"let = 5;",
+			[]string{
+				"1:5: expected next token to be IDENT, got = instead",
+				"1:5: no prefix parse function for = found",
+			},
+		},
+		{
+			"let x 5;",
+			[]string{
+				"1:7: expected next token to be =, got INT instead",
+			},
+		},
+		{
+			"let x = ;",
+			[]string{
+				"1:9: missing value in let statement",
+			},
+		},
+		{
+			"let x =",
+			[]string{
+				"1:8: unexpected end of input",
+			},
+		},
+		{
+			"1 +",
+			[]string{
+				"1:4: unexpected end of input",
+			},
+		},
+		{
+			"add(1, [2, {\"k\": ",
+			[]string{
+				"1:18: unexpected end of input",
+			},
+		},
+		{
+			"let",
+			[]string{
+				"1:4: unexpected end of input",
+			},
+		},
+		{
+			"{ return 1 +",
+			[]string{
+				"1:13: unexpected end of input",
+			},
+		},
+		{
+			"(1 + 2 *",
+			[]string{
+				"1:9: unexpected end of input",
+			},
+		},
+		{
+			"{ {",
+			[]string{
+				"1:3: block not terminated",
+			},
+		},
+		{
+			"add(1, 2",
+			[]string{
+				"1:9: unexpected end of input",
+			},
+		},
+		{
+			"let x = [1, 2",
+			[]string{
+				"1:14: unexpected end of input",
+			},
+		},
+		{
+			"(1 + 2",
+			[]string{
+				"1:7: unexpected end of input",
+			},
+		},
+		{
+			"(1 + 2 // comment\n/// doc\n",
+			[]string{
+				"3:1: unexpected end of input",
+			},
+		},
+		{
+			"add(1, 2\n)",
+			[]string{
+				"1:9: expected next token to be ), got newline instead",
+				"2:1: no prefix parse function for ) found",
+			},
+		},
+		{
+			"(1 + 2;",
+			[]string{
+				"1:7: expected next token to be ), got ; instead",
+			},
+		},
+		{
+			"let x = 1 +;",
+			[]string{
+				"1:12: no prefix parse function for ; found",
+			},
+		},
+		{
+			"1 +\n  ;",
+			[]string{
+				"2:3: no prefix parse function for ; found",
+			},
+		},
+		{
+			"a + b += 1;",
+			[]string{
+				"1:7: cannot assign to (a + b)",
+			},
+		},
+		{
+			"a + 99999999999999999999 += 1;",
+			[]string{
+				`1:5: failed to parse integer literal: strconv.ParseInt: parsing "99999999999999999999": value out of range`,
+				"1:26: cannot assign to this expression",
+			},
+		},
+		{
+			"-99999999999999999999 += 1;",
+			[]string{
+				`1:2: failed to parse integer literal: strconv.ParseInt: parsing "99999999999999999999": value out of range`,
+				"1:23: cannot assign to this expression",
+			},
+		},
+		{
+			"f(,) += 1;",
+			[]string{
+				"1:3: no prefix parse function for , found",
+				"1:6: cannot assign to this expression",
+			},
+		},
+		{
+			"a |> 5;",
+			[]string{
+				"1:3: pipeline target must be a function call or name, got 5",
+			},
+		},
+		{
+			"a |> b + 1e999;",
+			[]string{
+				`1:10: failed to parse float literal: strconv.ParseFloat: parsing "1e999": value out of range`,
+			},
+		},
+		{
+			"a |> !;",
+			[]string{
+				"1:7: no prefix parse function for ; found",
+			},
+		},
+		{
+			"a |> 1 +;",
+			[]string{
+				"1:9: no prefix parse function for ; found",
+			},
+		},
+		{
+			"a |> [,]",
+			[]string{
+				"1:7: no prefix parse function for , found",
+			},
+		},
+		{
+			"99999999999999999999;",
+			[]string{
+				`1:1: failed to parse integer literal: strconv.ParseInt: parsing "99999999999999999999": value out of range`,
+			},
+		},
+		{
+			"a + $b;",
+			[]string{
+				"1:5: unexpected '$' — did you mean to start an identifier?",
+			},
+		},
+		{
+			"let x 5 & 6;",
+			[]string{
+				"1:9: '&' is not an operator — use '&&'",
+				"1:7: expected next token to be =, got INT instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errs := p.Errors()
+		if len(errs) != len(tt.expected) {
+			t.Fatalf("input %q: want %d errors, got %d: %q", tt.input, len(tt.expected), len(errs), errs)
+		}
+		for i, want := range tt.expected {
+			if errs[i] != want {
+				t.Errorf("input %q: error #%d\n\twant: %s\n\t got: %s", tt.input, i, want, errs[i])
+			}
+		}
+	}
+}
+
+// testPrograms are the inputs of the parser tests. Every prefix of them is
+// parsed by TestParseIncompleteInput.
+var testPrograms = []string{
+	"let x = 5;\nlet y = x;\nlet foobar = y * 2 + 838383;\n",
+	"return 5;\nreturn x;\nreturn add(x, 838383);\nreturn\n{ return }\nreturn",
+	"let x = 5\nlet y = x; return y\nadd(x, y)\nx + y",
+	"const answer = 42;\nlet five = 5;\n",
+	"0x_7FFF_FFFF_FFFF_FFFF + 0b1010 * 1e-9 / .5",
+	"\"Hello ${name}, ${a + b}!\" + \"\\${x} ${\"${y}\" 🤗 f}\" + \"${`raw`}\"",
+	`c == '\n' || c == 'é'`,
+	"!-a * b <= c == d >= e && f != g || h % i",
+	"!(true == false) && (1 + (2 * 3)) > -(4)",
+	"x -= y *= 2 + 3; x += a |> f(1) 🤗 g(2, 3) |> h",
+	"add(a * [1, 2, 3, 4][b * c], f(x)[0](y), -a[0])",
+	`{"one": 0 + 1, "two": [10 - 8], 3: {}}`,
+	"{ \"k\" }\nf({ \"k\": v })\n{\n\tx\n\t{}\n}",
+	"{\"k\": v}\n{\"a\": 1, \"b\": 2,}[\"a\"] + 1\n{ x; {\"k\": 1} }",
+	"// sum of two numbers\na + /* inline */ b; // trailing\n",
+	"/// doc comment\nlet y = 4; /// trailing\n",
+	"let = 5;\nlet x 5 & 6;\na + $b;\na |> 5;",
+	"a |> b + 1e999;\na |> !;\na |> 1 +;\na |> [,]",
+	"a + 99999999999999999999 += 1;\n-99999999999999999999 += 1;\n(1 +) += 2;\nf(,) += 1",
+}
+
+func TestParseIncompleteInput(t *testing.T) {
+	for _, input := range testPrograms {
+		for i := 0; i <= len(input); i++ {
+			prefix := input[:i]
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				p := parser.New(lexer.New(prefix))
+				p.ParseProgram()
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("parsing %q does not terminate", prefix)
+			}
+		}
+	}
+}
+
+func testLetStatement(t *testing.T, s ast.Statement, name string) {
+	t.Helper()
+
+	if s.TokenLiteral() != "let" {
+		t.Errorf("s.TokenLiteral not 'let', got=%q", s.TokenLiteral())
+	}
+
+	letStmt, ok := s.(*ast.LetStatement)
+	if !ok {
+		t.Errorf("s not *ast.LetStatement, got=%T", s)
+	}
+
+	if letStmt.Name.Value != name {
+		t.Errorf("letStmt.Name.Value not '%s', got=%s", name, letStmt.Name.Value)
+	}
 
 	if letStmt.Name.TokenLiteral() != name {
 		t.Errorf("letStmt.Name.TokenLiteral() not '%s', got=%s", name, letStmt.Name.TokenLiteral())
 	}
 }
 
+func testLiteralExpression(t *testing.T, exp ast.Expression, expected any) bool {
+	t.Helper()
+
+	switch v := expected.(type) {
+	case int:
+		return testIntegerLiteral(t, exp, int64(v))
+	case int64:
+		return testIntegerLiteral(t, exp, v)
+	case string:
+		return testIdentifier(t, exp, v)
+	case bool:
+		return testBooleanLiteral(t, exp, v)
+	}
+	t.Errorf("type of exp not handled, got %T", exp)
+	return false
+}
+
+func testIdentifier(t *testing.T, exp ast.Expression, value string) bool {
+	ident, ok := exp.(*ast.Identifier)
+	if !ok {
+		t.Errorf("exp not *ast.Identifier, got %T", exp)
+		return false
+	}
+
+	if ident.Value != value {
+		t.Errorf("ident.Value not %s, got %s", value, ident.Value)
+		return false
+	}
+
+	if ident.TokenLiteral() != value {
+		t.Errorf("ident.TokenLiteral not %s, got=%s", value, ident.TokenLiteral())
+		return false
+	}
+	return true
+}
+
+func testBooleanLiteral(t *testing.T, exp ast.Expression, value bool) bool {
+	b, ok := exp.(*ast.Boolean)
+	if !ok {
+		t.Errorf("exp not *ast.Boolean, got %T", exp)
+		return false
+	}
+
+	if b.Value != value {
+		t.Errorf("b.Value not %t, got %t", value, b.Value)
+		return false
+	}
+
+	if b.TokenLiteral() != fmt.Sprintf("%t", value) {
+		t.Errorf("b.TokenLiteral not %t, got=%s", value, b.TokenLiteral())
+		return false
+	}
+	return true
+}
+
 func testIntegerLiteral(t *testing.T, il ast.Expression, value int64) bool {
 	integ, ok := il.(*ast.IntegerLiteral)
 	if !ok {
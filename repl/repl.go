@@ -6,7 +6,6 @@ import (
 	"io"
 
 	"github.com/antklim/go-inter/lexer"
-	"github.com/antklim/go-inter/token"
 )
 
 const PROMPT = ">> "
@@ -30,7 +29,7 @@ func Start(in io.Reader, out io.Writer) {
 
 		l := lexer.New(line)
 
-		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		for tok := range l.All() {
 			fmt.Fprintf(out, "%+v\n", tok)
 		}
 	}
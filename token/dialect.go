@@ -0,0 +1,61 @@
+package token
+
+import "maps"
+
+// Dialect is a variant of the language defined by its reserved words and the
+// operators it supports. A Dialect is immutable; derive a new one from the
+// Keywords and Operators of an existing Dialect.
+type Dialect struct {
+	keywords  map[string]TokenType
+	operators [NumTokenTypes]bool
+}
+
+// Default is the dialect used unless another one is configured.
+var Default = NewDialect(keywords, operators)
+
+// NewDialect returns a dialect that reserves the identifiers in keywords,
+// which maps each spelling to its token type, and supports the operators
+// listed in ops.
+func NewDialect(keywords map[string]TokenType, ops []TokenType) *Dialect {
+	d := &Dialect{keywords: maps.Clone(keywords)}
+	for _, op := range ops {
+		d.operators[op] = true
+	}
+	return d
+}
+
+// LookupIdent returns the keyword token type of s, or IDENT if s is not a
+// keyword of the dialect.
+func (d *Dialect) LookupIdent(s string) TokenType {
+	if tt, ok := d.keywords[s]; ok {
+		return tt
+	}
+	return IDENT
+}
+
+// IsOperator reports whether t is one of the operators supported by the
+// dialect.
+func (d *Dialect) IsOperator(t TokenType) bool {
+	return d.operators[t]
+}
+
+// Keywords returns a copy of the keyword table of the dialect.
+func (d *Dialect) Keywords() map[string]TokenType {
+	return maps.Clone(d.keywords)
+}
+
+// Operators returns the operators supported by the dialect.
+func (d *Dialect) Operators() []TokenType {
+	var ops []TokenType
+	for t, ok := range d.operators {
+		if ok {
+			ops = append(ops, TokenType(t))
+		}
+	}
+	return ops
+}
+
+// IsOperator reports whether t is an operator token in any dialect.
+func IsOperator(t TokenType) bool {
+	return Default.IsOperator(t)
+}
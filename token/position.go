@@ -0,0 +1,36 @@
+package token
+
+import "fmt"
+
+// Position describes a location in the source. A Position is valid when its
+// Line is greater than 0.
+type Position struct {
+	Filename string
+	Offset   int // byte offset, starting at 0
+	Line     int // line number, starting at 1
+	Column   int // column number, starting at 1 (byte count)
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in one of the forms:
+//
+//	file:line:column    valid position with file name
+//	line:column         valid position without file name
+//	file                invalid position with file name
+//	-                   invalid position without file name
+func (p Position) String() string {
+	s := p.Filename
+	if p.IsValid() {
+		if s != "" {
+			s += ":"
+		}
+		s += fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
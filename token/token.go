@@ -1,47 +1,88 @@
 package token
 
-type TokenType string
+// TokenType identifies the kind of a token. Its String method returns the
+// symbol of operators and punctuation, and the constant name otherwise.
+type TokenType uint8
+
+//go:generate stringer -type=TokenType -linecomment
 
 const (
-	ILLEGAL TokenType = "ILLEGAL"
-	EOF     TokenType = "EOF"
-
-	IDENT TokenType = "IDENT"
-	INT   TokenType = "INT"
-
-	ASSIGN   TokenType = "="
-	PLUS     TokenType = "+"
-	MINUS    TokenType = "-"
-	ASTERISK TokenType = "*"
-
-	BANG      TokenType = "!"
-	PERIOD    TokenType = "."
-	COMMA     TokenType = ","
-	SEMICOLON TokenType = ";"
-	SLASH     TokenType = "/"
-
-	LPAREN TokenType = "("
-	RPAREN TokenType = ")"
-	LBRACE TokenType = "{"
-	RBRACE TokenType = "}"
-	LT     TokenType = "<"
-	GT     TokenType = ">"
-
-	EQ     TokenType = "=="
-	NOT_EQ TokenType = "!="
-
-	FUNCTION TokenType = "FUNCTION"
-	LET      TokenType = "LET"
-	RETURN   TokenType = "RETURN"
-	IF       TokenType = "IF"
-	ELSE     TokenType = "ELSE"
-
-	TRUE  TokenType = "TRUE"
-	FALSE TokenType = "FALSE"
-
-	HUG TokenType = "🤗"
+	ILLEGAL TokenType = iota
+	EOF
+	COMMENT
+	// A /// line comment, documentation for the code below it.
+	DOC_COMMENT
+
+	IDENT
+	INT
+	FLOAT
+	STRING
+	CHAR
+
+	// The parts of an interpolated string "a${x}b${y}c": STRING_START is
+	// "a${, STRING_MID is }b${ and STRING_END is }c".
+	STRING_START
+	STRING_MID
+	STRING_END
+
+	ASSIGN   // =
+	PLUS     // +
+	MINUS    // -
+	ASTERISK // *
+	PERCENT  // %
+
+	PLUS_ASSIGN     // +=
+	MINUS_ASSIGN    // -=
+	ASTERISK_ASSIGN // *=
+	SLASH_ASSIGN    // /=
+
+	BANG      // !
+	PERIOD    // .
+	COMMA     // ,
+	COLON     // :
+	SEMICOLON // ;
+	SLASH     // /
+
+	LPAREN   // (
+	RPAREN   // )
+	LBRACE   // {
+	RBRACE   // }
+	LBRACKET // [
+	RBRACKET // ]
+	LT       // <
+	GT       // >
+
+	EQ     // ==
+	NOT_EQ // !=
+	LT_EQ  // <=
+	GT_EQ  // >=
+
+	AND // &&
+	OR  // ||
+
+	FUNCTION
+	LET
+	RETURN
+	IF
+	ELSE
+
+	TRUE
+	FALSE
+
+	// Keywords that are not reserved in the default dialect.
+	WHILE
+	CONST
+
+	// The pipeline operator, also spelled |>.
+	HUG // 🤗
+
+	numTokenTypes
 )
 
+// NumTokenTypes is the number of token types. Every TokenType is less than
+// NumTokenTypes, so it can be used as the length of lookup tables.
+const NumTokenTypes = int(numTokenTypes)
+
 var keywords = map[string]TokenType{
 	"fn":     FUNCTION,
 	"let":    LET,
@@ -52,14 +93,30 @@ var keywords = map[string]TokenType{
 	"false":  FALSE,
 }
 
+// operators are the operator tokens of the default dialect.
+var operators = []TokenType{
+	ASSIGN, PLUS, MINUS, ASTERISK, PERCENT, SLASH, BANG,
+	PLUS_ASSIGN, MINUS_ASSIGN, ASTERISK_ASSIGN, SLASH_ASSIGN,
+	LT, GT, EQ, NOT_EQ, LT_EQ, GT_EQ, AND, OR,
+	HUG,
+}
+
+// LookupIdent returns the keyword token type of s in the default dialect, or
+// IDENT if s is not a keyword.
 func LookupIdent(s string) TokenType {
-	if tt, ok := keywords[s]; ok {
-		return tt
-	}
-	return IDENT
+	return Default.LookupIdent(s)
 }
 
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position // position of the first character of the token
+
+	// Leading and Trailing hold the trivia (whitespace and comments) around
+	// the token and Text holds its exact source text. They are only set in
+	// lossless mode, where Leading + Text + Trailing of all tokens reproduces
+	// the source.
+	Leading  string
+	Text     string
+	Trailing string
 }